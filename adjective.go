@@ -0,0 +1,110 @@
+package gown
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Result of the antonym search on an adjective, following the adjective cluster
+// model: head synsets have direct antonyms and the satellites around a head
+// have its antonyms as indirect antonyms
+type AdjAntonyms struct {
+	Mode       int     // DIRECT_ANT, INDIRECT_ANT or PERTAINYM
+	Head       *Synset // head synset of the cluster (nil for PERTAINYM)
+	HeadWord   string  // head word of a satellite ("" otherwise)
+	Antonyms   []WordLink
+	Pertainyms []WordLink // nouns or adjectives the adjective pertains to
+}
+
+// Returns the head synset of an adjective and its head word
+// A head synset is its own head and has no head word
+func (wndb *WordNetDb) HeadOf(s *Synset) (*Synset, string, error) {
+	switch s.Pos {
+	case 'a':
+		return s, "", nil
+	case 's':
+		head, err := wndb.headSynset(s)
+		if err != nil {
+			return nil, "", err
+		}
+		return head, head.Words[0].Lemma, nil
+	}
+	return nil, "", errors.New(fmt.Sprintf("Synset %08d is not an adjective", s.Offset))
+}
+
+// Antonym search on an adjective synset (getantmode and traceadjant in the
+// C library): direct antonyms of a head synset, indirect antonyms through the
+// head synset of a satellite, or pertainyms of a relational adjective
+func (wndb *WordNetDb) AdjectiveAntonyms(s *Synset) (*AdjAntonyms, error) {
+	head, headWord, err := wndb.HeadOf(s)
+	if err != nil {
+		return nil, err
+	}
+	res := &AdjAntonyms{HeadWord: headWord}
+	if s.Pos == 's' { // indirect antonyms are the antonyms of the head word only
+		res.Antonyms, err = wndb.WordPointers(head, 1, ptrtyp[ANTPTR])
+	} else {
+		res.Antonyms, err = wndb.ResolvePointers(head, ptrtyp[ANTPTR])
+	}
+	if err != nil {
+		return nil, err
+	}
+	res.Pertainyms, err = wndb.ResolvePointers(s, ptrtyp[PERTPTR])
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case s.Pos == 's':
+		res.Mode = INDIRECT_ANT
+		res.Head = head
+	case len(res.Antonyms) > 0:
+		res.Mode = DIRECT_ANT
+		res.Head = head
+	default:
+		res.Mode = PERTAINYM
+	}
+	return res, nil
+}
+
+// Syntactic marker of an adjective: the position it's restricted to
+type AdjMarker int
+
+// "(p)", "(a)", "(ip)" or "" for ALL_POS
+func (m AdjMarker) String() string {
+	if m < ALL_POS || m > IPADJ {
+		return ""
+	}
+	return adjclass[m]
+}
+
+// Tells if the word can be used in position (PADJ for predicative, NPADJ for
+// attributive, IPADJ for immediately postnominal)
+// Words without marker can be used in any position
+func (w Word) UsableAs(position AdjMarker) bool {
+	return w.Marker == ALL_POS || position == ALL_POS || w.Marker == position
+}
+
+// Returns the adjective senses of word, in sense number order, where it can be
+// used in position (all the senses for ALL_POS)
+func (wndb *WordNetDb) AdjectiveSenses(word string, position AdjMarker) ([]*Synset, error) {
+	lemma := string(strToLower([]byte(word)))
+	offsets, err := wndb.Index.Lookup([]byte(lemma), ADJ)
+	if err != nil {
+		return nil, err
+	}
+	senses := make([]*Synset, 0, len(offsets))
+	for _, offset := range offsets {
+		s, err := wndb.Synset(ADJ, offset)
+		if err != nil {
+			return nil, err
+		}
+		for _, w := range s.Words {
+			if strings.ToLower(w.Lemma) == lemma && w.UsableAs(position) {
+				senses = append(senses, s)
+				break
+			}
+		}
+	}
+	return senses, nil
+}
@@ -0,0 +1,86 @@
+package gown
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"io"
+	"io/fs"
+	"path"
+	"strings"
+	"time"
+)
+
+// Reads a tar.gz archive in memory and returns it as a fs.FS to be used with OpenFS
+// If the dictionary files are under a directory in the archive use fs.Sub, e.g.
+//
+//	fsys, err := TarGzFS(r)
+//	sub, err := fs.Sub(fsys, "WordNet-3.0/dict")
+//	wndb, err := OpenFS(sub)
+func TarGzFS(r io.Reader) (fs.FS, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, err
+	}
+	defer gz.Close()
+
+	fsys := memFS{}
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return fsys, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+		content, err := io.ReadAll(tr)
+		if err != nil {
+			return nil, err
+		}
+		name := strings.TrimPrefix(path.Clean(hdr.Name), "/")
+		fsys[name] = &memFileInfo{name: path.Base(name), data: content, mode: fs.FileMode(hdr.Mode).Perm(), modTime: hdr.ModTime}
+	}
+}
+
+// In-memory fs.FS holding the regular files of an archive
+// Only the files can be opened, directories are not listed
+type memFS map[string]*memFileInfo
+
+type memFileInfo struct {
+	name    string
+	data    []byte
+	mode    fs.FileMode
+	modTime time.Time
+}
+
+// An open file of a memFS, it implements io.ReaderAt so the files are
+// searched without copying them
+type memFile struct {
+	*bytes.Reader
+	info *memFileInfo
+}
+
+func (m memFS) Open(name string) (fs.File, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrInvalid}
+	}
+	info, ok := m[name]
+	if !ok {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+	}
+	return &memFile{bytes.NewReader(info.data), info}, nil
+}
+
+func (f *memFile) Stat() (fs.FileInfo, error) { return f.info, nil }
+func (f *memFile) Close() error               { return nil }
+
+func (fi *memFileInfo) Name() string       { return fi.name }
+func (fi *memFileInfo) Size() int64        { return int64(len(fi.data)) }
+func (fi *memFileInfo) Mode() fs.FileMode  { return fi.mode }
+func (fi *memFileInfo) ModTime() time.Time { return fi.modTime }
+func (fi *memFileInfo) IsDir() bool        { return false }
+func (fi *memFileInfo) Sys() interface{}   { return nil }
@@ -0,0 +1,99 @@
+package gown
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"io/fs"
+	"os"
+	"testing"
+)
+
+// Builds a tar.gz and a zip archive with the files of testdata/dict under prefix
+func testArchives(t *testing.T, prefix string) (tgz, zipped []byte) {
+	entries, err := os.ReadDir("testdata/dict")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var tgzBuf, zipBuf bytes.Buffer
+	gz := gzip.NewWriter(&tgzBuf)
+	tw := tar.NewWriter(gz)
+	zw := zip.NewWriter(&zipBuf)
+	for _, e := range entries {
+		content, err := os.ReadFile("testdata/dict/" + e.Name())
+		if err != nil {
+			t.Fatal(err)
+		}
+		name := prefix + e.Name()
+		if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(content)), Typeflag: tar.TypeReg}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tw.Write(content); err != nil {
+			t.Fatal(err)
+		}
+		w, err := zw.CreateHeader(&zip.FileHeader{Name: name, Method: zip.Deflate})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := w.Write(content); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, c := range []interface{ Close() error }{tw, gz, zw} {
+		if err := c.Close(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return tgzBuf.Bytes(), zipBuf.Bytes()
+}
+
+func TestOpenArchives(t *testing.T) {
+	tgz, zipped := testArchives(t, "WordNet-3.0/dict/")
+
+	tgzFS, err := TarGzFS(bytes.NewReader(tgz))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the deflated entries of a zip file don't implement io.ReaderAt
+	zipFS, err := zip.NewReader(bytes.NewReader(zipped), int64(len(zipped)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir, err := Open("testdata/dict", WithLogger(nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer dir.Close()
+	want, err := dir.Index.Lookup([]byte("dog"), NOUN)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantLine, err := dir.dataLookup(dir.Data[NOUN], want[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for name, fsys := range map[string]fs.FS{"tar.gz": tgzFS, "zip": zipFS} {
+		sub, err := fs.Sub(fsys, "WordNet-3.0/dict")
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, indexType := range []int{INDEX_IN_MEMORY, INDEX_BIN_SEARCH} {
+			wndb, err := OpenFS(sub, WithIndex(indexType), WithLogger(nil))
+			if err != nil {
+				t.Fatalf("%s: OpenFS: %v", name, err)
+			}
+			offsets, err := wndb.Index.Lookup([]byte("dog"), NOUN)
+			if err != nil || len(offsets) != len(want) || offsets[0] != want[0] {
+				t.Errorf("%s (index %d): Lookup(dog) = %v, %v; want %v", name, indexType, offsets, err, want)
+			} else if line, err := wndb.dataLookup(wndb.Data[NOUN], offsets[0]); err != nil || !bytes.Equal(line, wantLine) {
+				t.Errorf("%s (index %d): dataLookup(%d) = %q, %v; want %q", name, indexType, offsets[0], line, err, wantLine)
+			}
+			if err := wndb.Close(); err != nil {
+				t.Errorf("%s: Close: %v", name, err)
+			}
+		}
+	}
+}
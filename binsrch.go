@@ -1,58 +1,235 @@
 package gown
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
 	"io"
-	"os"
+	"io/fs"
 )
 
 const (
-	KEY_LEN = 1024
-	LINE_LEN = 1024*25
+	KEY_LEN  = 1024
+	LINE_LEN = 1024 * 25
 )
 
-var (
-//	line [LINE_LEN]byte
-	last_bin_search_offset int64 = 0
-)
+// Disk-backed Indexer
+// The index.* files are sorted by lemma, so we can answer Lookup with a binary
+// search over the files (as bin_search does in the C library) instead of
+// loading all the lemmas in memory
+type indexFile struct {
+	fh   io.ReaderAt
+	size int64
+}
+
+type binSearchIndex [NUMPARTS + 1]*indexFile
+
+// A sorted file whose lines are looked up by their first field
+// (index.sense, cntlist.rev, sentidx.vrb, ...)
+type lineIndex interface {
+	lookupLine([]byte) ([]byte, error)
+}
+
+// In-memory lineIndex
+type lineMap map[string][]byte
+
+func (b *binSearchIndex) Lookup(word []byte, pos int) ([]int64, error) {
+	newIndexInfo, err := b.lookupInfo(word, pos)
+	if err != nil {
+		return nil, err
+	}
+	return newIndexInfo.offsets, nil
+}
 
-func read_index(offset int64, fh io.ReaderAt) []byte { // io.ReaderAt?? as a pointer??
-	line := make([]byte, LINE_LEN)
-	n, err := fh.ReadAt(offset) // We may be reading too much, no?
-	if err != nil && err != os.EOF {
-		fmt.Fprintf(os.Stderr, "An error occurred while trying to read from file: %s\n", err)
-		os.Exit(1)
+func (b *binSearchIndex) lookupInfo(word []byte, pos int) (*indexInfo, error) {
+	if pos < 1 || pos > NUMPARTS {
+		return nil, errors.New(fmt.Sprintf("Invalid part of speech: %d", pos))
+	}
+	line, err := b[pos].binSearch(word)
+	if err != nil {
+		return nil, err
 	}
-	return line
+	return parseIndexLine(line)
 }
 
-func bin_search(searchkey []byte, fh io.Reader) []byte {
-	var c int
+// Returns the line of the file whose first field is searchkey
+func (f *indexFile) binSearch(searchkey []byte) ([]byte, error) {
 	var top, mid, bot, diff int64
-	var length int
 
-	diff = 666
-	top = 0
-	fileStats, err := fh.Stat()
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "Unable to get the stats of file: %s\n", err)
-		os.Exit(1)
+	if f.size <= 1 || len(searchkey) == 0 { // no line to find
+		return nil, ERR_MSG(UNKNOWN_WORD)
 	}
-	bot = fileStats.Size
+	top = 0
+	bot = f.size
 	mid = (bot - top) / 2
-	
-	line := make([]byte, LINE_LEN)
 
 	for {
-		newpos, err := fh.Seek(mid - 1, os.SEEK_SET)
+		line, err := lineAfter(f.fh, mid-1)
+		if err != nil {
+			return nil, err
+		}
+		key := line
+		if length := bytes.IndexByte(line, ' '); length >= 0 {
+			key = line[:length]
+		}
+		cmp := bytes.Compare(key, searchkey)
+		switch {
+		case len(line) == 0: // past the last line
+			cmp = 1
+		case line[0] == ' ': // license header, sorts before every key
+			cmp = -1
+		}
+		switch {
+		case cmp < 0:
+			top = mid
+			diff = (bot - top) / 2
+			mid = top + diff
+		case cmp > 0:
+			bot = mid
+			diff = (bot - top) / 2
+			mid = top + diff
+		default:
+			return line, nil
+		}
+		if diff == 0 {
+			return nil, ERR_MSG(UNKNOWN_WORD)
+		}
+	}
+}
+
+func (f *indexFile) lookupLine(key []byte) ([]byte, error) {
+	return f.binSearch(key)
+}
+
+func (m lineMap) lookupLine(key []byte) ([]byte, error) {
+	line, ok := m[string(key)]
+	if !ok {
+		return nil, ERR_MSG(UNKNOWN_WORD)
+	}
+	return line, nil
+}
+
+// Returns the first full line starting after offset
+// (or the first line of the file if offset is 0)
+func lineAfter(fh io.ReaderAt, offset int64) ([]byte, error) {
+	if offset > 0 {
+		rest, err := readLineAt(fh, offset, KEY_LEN)
+		if err != nil {
+			return nil, err
+		}
+		offset += int64(len(rest)) + 1
+	}
+	return readLineAt(fh, offset, KEY_LEN)
+}
+
+// Reads the line starting at offset (without the trailing newline)
+// buffsize bytes are read each time until a newline is found
+func readLineAt(fh io.ReaderAt, offset int64, buffsize int) ([]byte, error) {
+	buffer := make([]byte, buffsize)
+	line := make([]byte, 0, buffsize)
+	for {
+		n, err := fh.ReadAt(buffer, offset)
+		if err != nil && err != io.EOF {
+			return nil, err
+		}
+		if until := bytes.IndexByte(buffer[:n], '\n'); until >= 0 { // We have a full line
+			return append(line, buffer[:until]...), nil
+		}
+		line = append(line, buffer[:n]...)
+		if err == io.EOF {
+			return line, nil
+		}
+		offset += int64(n)
+	}
+}
+
+// Loads a disk-backed Indexer
+// The index files are kept open and searched on each Lookup
+func loadBinSearchIndex(fsys fs.FS) (Indexer, error) {
+	var index binSearchIndex
+
+	for i := 1; i <= NUMPARTS; i++ {
+		indexpath := "index." + partnames[i]
+		indexfh, err := openIndexFile(fsys, indexpath)
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error while Seeking: %s\n", err)
-			os.Exit(1)
+			return nil, err
+		}
+		index[i] = indexfh
+	}
+
+	return &index, nil
+}
+
+// Opens a sorted file for binary searching
+func openIndexFile(fsys fs.FS, name string) (*indexFile, error) {
+	fh, size, err := openReaderAt(fsys, name)
+	if err != nil {
+		return nil, err
+	}
+	return &indexFile{fh: fh, size: size}, nil
+}
+
+// Opens name in fsys for random access
+// Files that can't be read at arbitrary offsets (e.g. compressed entries of a zip
+// file) are read in memory
+func openReaderAt(fsys fs.FS, name string) (io.ReaderAt, int64, error) {
+	fh, err := fsys.Open(name)
+	if err != nil {
+		return nil, 0, err
+	}
+	if ra, ok := fh.(io.ReaderAt); ok {
+		fileStats, err := fh.Stat()
+		if err != nil {
+			fh.Close()
+			return nil, 0, err
+		}
+		return ra, fileStats.Size(), nil
+	}
+	defer fh.Close()
+	content, err := io.ReadAll(fh)
+	if err != nil {
+		return nil, 0, err
+	}
+	return bytes.NewReader(content), int64(len(content)), nil
+}
+
+// Loads a sorted file in memory, indexed by the first field of the lines
+func loadLineMap(fsys fs.FS, name string) (lineMap, error) {
+	content, err := fs.ReadFile(fsys, name)
+	if err != nil {
+		return nil, err
+	}
+	m := make(lineMap)
+	for _, line := range bytes.Split(content, []byte{'\n'}) {
+		line = bytes.TrimRight(line, "\r")
+		if len(line) == 0 || line[0] == ' ' { // header line
+			continue
+		}
+		key := line
+		if length := bytes.IndexByte(line, ' '); length >= 0 {
+			key = line[:length]
+		}
+		m[string(key)] = line
+	}
+	return m, nil
+}
+
+func (f *indexFile) Close() error {
+	if closer, ok := f.fh.(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
+
+func (b *binSearchIndex) Close() error {
+	var err error
+	for _, f := range b {
+		if f == nil {
+			continue
 		}
-		if newpos != mid - 1 {
-			fmt.Fprintf(os.Stderr, "Something happened while Seeking: %d != %d\n", newpos, mid-1)
-			os.Exit(1)
+		if e := f.Close(); e != nil && err == nil {
+			err = e
 		}
-		
 	}
+	return err
 }
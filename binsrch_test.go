@@ -0,0 +1,81 @@
+package gown
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// Sorted file of nlines lines "keyNNN fields...", with the license header of
+// the WordNet files if header is true
+// Every 7th line is longer than KEY_LEN to be read in several chunks
+func sortedFile(nlines int, header bool) (*indexFile, []string) {
+	var buf bytes.Buffer
+	if header {
+		buf.WriteString("  1 This software and database is being provided to you, the LICENSEE, by  \n")
+		buf.WriteString("  2 Princeton University under the following license.  \n")
+	}
+	lines := make([]string, nlines)
+	for i := range lines {
+		lines[i] = fmt.Sprintf("key%03d n 1 0 1 0 %08d", i*2, i)
+		if i%7 == 3 {
+			lines[i] += " " + strings.Repeat("x", KEY_LEN+100)
+		}
+		buf.WriteString(lines[i] + "  \n")
+	}
+	data := buf.Bytes()
+	return &indexFile{bytes.NewReader(data), int64(len(data))}, lines
+}
+
+func TestBinSearch(t *testing.T) {
+	for _, header := range []bool{false, true} {
+		for _, nlines := range []int{0, 1, 2, 3, 10, 64, 257} {
+			f, lines := sortedFile(nlines, header)
+			for i, want := range lines {
+				key := fmt.Sprintf("key%03d", i*2)
+				line, err := f.binSearch([]byte(key))
+				if err != nil || strings.TrimRight(string(line), " ") != want {
+					t.Errorf("header %v, %d lines: binSearch(%s) = %.40q, %v", header, nlines, key, line, err)
+				}
+			}
+			missing := []string{"", "a", "key", "key00", "key001", fmt.Sprintf("key%03d", nlines*2-1), fmt.Sprintf("key%03d", nlines*2), "zzz"}
+			for _, key := range missing {
+				if line, err := f.binSearch([]byte(key)); err != ERR_MSG(UNKNOWN_WORD) {
+					t.Errorf("header %v, %d lines: binSearch(%s) = %.40q, %v; want UNKNOWN_WORD", header, nlines, key, line, err)
+				}
+			}
+		}
+	}
+}
+
+func TestReadLineAt(t *testing.T) {
+	data := []byte("first line\nsecond\n\nlast line without newline")
+	r := bytes.NewReader(data)
+	tests := []struct {
+		offset int64
+		want   string
+	}{
+		{0, "first line"},
+		{6, "line"},
+		{11, "second"},
+		{18, ""},
+		{19, "last line without newline"},
+		{int64(len(data)), ""},
+	}
+	for _, buffsize := range []int{1, 4, 1024} {
+		for _, test := range tests {
+			line, err := readLineAt(r, test.offset, buffsize)
+			if err != nil || string(line) != test.want {
+				t.Errorf("readLineAt(%d, %d) = %q, %v; want %q", test.offset, buffsize, line, err, test.want)
+			}
+		}
+	}
+	// lineAfter skips the rest of the line the offset is in
+	for offset, want := range map[int64]string{0: "first line", 3: "second", 10: "second", 12: "", 18: "last line without newline"} {
+		line, err := lineAfter(r, offset)
+		if err != nil || string(line) != want {
+			t.Errorf("lineAfter(%d) = %q, %v; want %q", offset, line, err, want)
+		}
+	}
+}
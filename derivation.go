@@ -0,0 +1,82 @@
+package gown
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// A pair of derivationally related words ("destroy#v#1" and "destruction#n#1")
+// Also used for participles ("broken#a#1" and "break#v#1") and pertainyms
+type Derivation struct {
+	From WordSense
+	To   WordSense
+}
+
+// Derivationally related forms of word, following the "+" lexical pointers of
+// each of its senses to the data file of their target part of speech
+// All the parts of speech are searched if pos is 0
+func (wndb *WordNetDb) Derivations(word string, pos int) ([]Derivation, error) {
+	return wndb.lexicalPairs(word, pos, ptrtyp[DERIVATION])
+}
+
+// Follows the lexical pointers with the symbol from each sense of word
+func (wndb *WordNetDb) lexicalPairs(word string, pos int, symbol string) ([]Derivation, error) {
+	if pos < 0 || pos > NUMPARTS {
+		return nil, errors.New(fmt.Sprintf("Invalid part of speech: %d", pos))
+	}
+	if pos == 0 {
+		derivs := make([]Derivation, 0, 2)
+		for pos = 1; pos <= NUMPARTS; pos++ {
+			d, err := wndb.lexicalPairs(word, pos, symbol)
+			if err != nil && err != ERR_MSG(UNKNOWN_WORD) {
+				return nil, err
+			}
+			derivs = append(derivs, d...)
+		}
+		return derivs, nil
+	}
+
+	lemma := string(strToLower([]byte(word)))
+	offsets, err := wndb.Index.Lookup([]byte(lemma), pos)
+	if err != nil {
+		return nil, err
+	}
+	derivs := make([]Derivation, 0, len(offsets))
+	for i, offset := range offsets {
+		s, err := wndb.Synset(pos, offset)
+		if err != nil {
+			return nil, err
+		}
+		from := WordSense{lemma, partchars[pos], i + 1}
+		d, err := wndb.synsetPairs(s, from, symbol)
+		if err != nil {
+			return nil, err
+		}
+		derivs = append(derivs, d...)
+	}
+	return derivs, nil
+}
+
+// Follows the pointers of s with the symbol from the word from
+func (wndb *WordNetDb) synsetPairs(s *Synset, from WordSense, symbol string) ([]Derivation, error) {
+	derivs := make([]Derivation, 0, 1)
+	for _, ptr := range s.Pointers {
+		if ptr.Symbol != symbol || ptr.Source < 1 || ptr.Source > len(s.Words) {
+			continue
+		}
+		if strings.ToLower(s.Words[ptr.Source-1].Lemma) != from.Lemma {
+			continue
+		}
+		target, err := wndb.Synset(getpos(ptr.Pos), ptr.Offset)
+		if err != nil {
+			return nil, err
+		}
+		to, err := wndb.wordSense(target, ptr.Target)
+		if err != nil {
+			return nil, err
+		}
+		derivs = append(derivs, Derivation{from, *to})
+	}
+	return derivs, nil
+}
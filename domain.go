@@ -0,0 +1,67 @@
+package gown
+
+import (
+	"errors"
+	"fmt"
+)
+
+// A domain of a synset or a member of a domain
+type DomainLink struct {
+	Kind   int     // pointer followed: CLASSIF_CATEGORY, CLASSIF_USAGE, CLASSIF_REGIONAL (domain) or CLASS_CATEGORY, CLASS_USAGE, CLASS_REGIONAL (member)
+	Synset *Synset // the domain or the member
+	Word   string  // word of Synset the pointer is to ("" for a semantic pointer)
+}
+
+// Domains of s: the topics (";c"), usages (";u") and regions (";r") it belongs to
+func (wndb *WordNetDb) Domains(s *Synset) ([]DomainLink, error) {
+	return wndb.domainLinks(s, CLASSIF_START, CLASSIF_END)
+}
+
+// Members of the domain d grouped by part of speech (NOUN, VERB, ADJ and ADV)
+// kind is CLASS_CATEGORY, CLASS_USAGE or CLASS_REGIONAL, or 0 for the members
+// of any kind
+func (wndb *WordNetDb) DomainMembers(d *Synset, kind int) ([NUMPARTS + 1][]DomainLink, error) {
+	var byPos [NUMPARTS + 1][]DomainLink
+	from, to := CLASS_START, CLASS_END
+	if kind != 0 {
+		if kind < CLASS_START || kind > CLASS_END {
+			return byPos, errors.New(fmt.Sprintf("Invalid domain member kind: %d", kind))
+		}
+		from, to = kind, kind
+	}
+	members, err := wndb.domainLinks(d, from, to)
+	if err != nil {
+		return byPos, err
+	}
+	for _, m := range members {
+		pos := getpos(m.Synset.Pos)
+		byPos[pos] = append(byPos[pos], m)
+	}
+	return byPos, nil
+}
+
+// Follows the pointers of s with a symbol in ptrtyp[from:to+1]
+func (wndb *WordNetDb) domainLinks(s *Synset, from, to int) ([]DomainLink, error) {
+	links := make([]DomainLink, 0, 1)
+	for _, ptr := range s.Pointers {
+		kind := 0
+		for i := from; i <= to; i++ {
+			if ptr.Symbol == ptrtyp[i] {
+				kind = i
+			}
+		}
+		if kind == 0 {
+			continue
+		}
+		target, err := wndb.Synset(getpos(ptr.Pos), ptr.Offset)
+		if err != nil {
+			return nil, err
+		}
+		link := DomainLink{Kind: kind, Synset: target}
+		if ptr.IsLexical() && ptr.Target >= 1 && ptr.Target <= len(target.Words) {
+			link.Word = target.Words[ptr.Target-1].Lemma
+		}
+		links = append(links, link)
+	}
+	return links, nil
+}
@@ -0,0 +1,99 @@
+package gown
+
+// Entailment tree of the verb s ("snore" => "sleep")
+func (wndb *WordNetDb) EntailmentTree(s *Synset, maxDepth int) (*SynsetTree, error) {
+	return wndb.TracePointers(s, []string{ptrtyp[ENTAILPTR]}, maxDepth)
+}
+
+// Cause tree of the verb s ("kill" => "die")
+func (wndb *WordNetDb) CauseTree(s *Synset, maxDepth int) (*SynsetTree, error) {
+	return wndb.TracePointers(s, []string{ptrtyp[CAUSETO]}, maxDepth)
+}
+
+// Verbs entailed by s ("*"), directly or, if transitive, through other entailments
+func (wndb *WordNetDb) Entailments(s *Synset, transitive bool) ([]*Synset, error) {
+	return wndb.closure(s, ptrtyp[ENTAILPTR], transitive)
+}
+
+// Verbs s causes (">"), directly or, if transitive, through other causes
+func (wndb *WordNetDb) Causes(s *Synset, transitive bool) ([]*Synset, error) {
+	return wndb.closure(s, ptrtyp[CAUSETO], transitive)
+}
+
+// Verbs that entail s ("which verbs entail sleep?"), directly or, if transitive,
+// through other entailments
+// The pointers only go from the entailing verb, so the whole data.verb file is read
+func (wndb *WordNetDb) EntailedBy(s *Synset, transitive bool) ([]*Synset, error) {
+	return wndb.inverseClosure(s, ptrtyp[ENTAILPTR], transitive)
+}
+
+// Verbs that cause s, directly or, if transitive, through other causes
+// The pointers only go from the causing verb, so the whole data.verb file is read
+func (wndb *WordNetDb) CausedBy(s *Synset, transitive bool) ([]*Synset, error) {
+	return wndb.inverseClosure(s, ptrtyp[CAUSETO], transitive)
+}
+
+// Synsets reached from s by the pointers with the symbol, breadth first and
+// each once
+func (wndb *WordNetDb) closure(s *Synset, symbol string, transitive bool) ([]*Synset, error) {
+	if !transitive {
+		return wndb.pointedSynsets(s, symbol)
+	}
+	tree, err := wndb.TracePointers(s, []string{symbol}, 0)
+	if err != nil {
+		return nil, err
+	}
+	seen := map[synsetId]bool{idOf(s): true}
+	found := make([]*Synset, 0, 2)
+	level := tree.Children
+	for len(level) > 0 {
+		next := make([]*SynsetTree, 0, len(level))
+		for _, node := range level {
+			next = append(next, node.Children...)
+			if !seen[idOf(node.Synset)] {
+				seen[idOf(node.Synset)] = true
+				found = append(found, node.Synset)
+			}
+		}
+		level = next
+	}
+	return found, nil
+}
+
+// Synsets of the data file of s having a pointer with the symbol to s (or, if
+// transitive, to one of the synsets found), breadth first and each once
+func (wndb *WordNetDb) inverseClosure(s *Synset, symbol string, transitive bool) ([]*Synset, error) {
+	pos := getpos(s.Pos)
+	sources := map[int64][]*Synset{} // target offset => synsets pointing to it
+	err := wndb.scanData(pos, []byte(" "+symbol+" "), func(src *Synset) error {
+		for _, ptr := range src.Pointers {
+			if ptr.Symbol == symbol && getpos(ptr.Pos) == pos {
+				sources[ptr.Offset] = append(sources[ptr.Offset], src)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	seen := map[int64]bool{s.Offset: true}
+	found := make([]*Synset, 0, 2)
+	for level := []int64{s.Offset}; len(level) > 0; {
+		next := make([]int64, 0, len(level))
+		for _, offset := range level {
+			for _, src := range sources[offset] {
+				if seen[src.Offset] {
+					continue
+				}
+				seen[src.Offset] = true
+				found = append(found, src)
+				next = append(next, src.Offset)
+			}
+		}
+		if !transitive {
+			break
+		}
+		level = next
+	}
+	return found, nil
+}
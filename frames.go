@@ -0,0 +1,119 @@
+package gown
+
+import (
+	"strings"
+)
+
+// Returns the frames that apply to the word number w (starting at 1) of the synset
+func (s *Synset) FramesFor(w int) []Frame {
+	frames := make([]Frame, 0, len(s.Frames))
+	for _, frame := range s.Frames {
+		if frame.Word == 0 || frame.Word == w {
+			frames = append(frames, frame)
+		}
+	}
+	return frames
+}
+
+// Returns the generic sentence frames that apply to the word number w (starting at 1)
+// of the synset, with the verb substituted ("Somebody ----s something" => "Somebody eats something")
+func (s *Synset) SentenceFrames(w int) []string {
+	if w < 1 || w > len(s.Words) {
+		return nil
+	}
+	verb := s.Words[w-1].Lemma
+	frames := s.FramesFor(w)
+	sentences := make([]string, len(frames))
+	for i, frame := range frames {
+		sentences[i] = frame.Render(verb)
+	}
+	return sentences
+}
+
+// Returns the generic text of the frame ("Somebody ----s something")
+func (f Frame) Text() string {
+	if f.Number < 1 || f.Number > NUMFRAMES {
+		return ""
+	}
+	return frametext[f.Number]
+}
+
+// Returns the text of the frame with verb substituted
+func (f Frame) Render(verb string) string {
+	return RenderFrame(f.Number, verb)
+}
+
+// Substitutes verb in the generic frame number
+// Only the first word of a collocation is inflected ("saw_wood" => "Somebody saws wood")
+func RenderFrame(number int, verb string) string {
+	if number < 1 || number > NUMFRAMES {
+		return ""
+	}
+	text := frametext[number]
+	words := strings.SplitN(strings.Replace(verb, "_", " ", -1), " ", 2)
+	rest := ""
+	if len(words) > 1 {
+		rest = " " + words[1]
+	}
+	text = strings.Replace(text, "----ing", presentParticiple(words[0])+rest, -1)
+	text = strings.Replace(text, "----s", thirdPerson(words[0])+rest, -1)
+	return text
+}
+
+// Third person singular of a verb ("eat" => "eats", "catch" => "catches", "cry" => "cries")
+func thirdPerson(verb string) string {
+	switch {
+	case verb == "be":
+		return "is"
+	case verb == "have":
+		return "has"
+	case strings.HasSuffix(verb, "s") ||
+		strings.HasSuffix(verb, "x") ||
+		strings.HasSuffix(verb, "z") ||
+		strings.HasSuffix(verb, "ch") ||
+		strings.HasSuffix(verb, "sh") ||
+		strings.HasSuffix(verb, "o"):
+		return verb + "es"
+	case strings.HasSuffix(verb, "y") && len(verb) > 1 && !isVowel(verb[len(verb)-2]):
+		return verb[:len(verb)-1] + "ies"
+	}
+	return verb + "s"
+}
+
+// Present participle of a verb ("eat" => "eating", "make" => "making", "die" => "dying")
+// The final consonant is only doubled for one-syllable verbs ("run" => "running"),
+// longer verbs would need their stress ("permit" => "permiting")
+func presentParticiple(verb string) string {
+	n := len(verb)
+	switch {
+	case verb == "be":
+		return "being"
+	case strings.HasSuffix(verb, "ie"):
+		return verb[:n-2] + "ying"
+	case strings.HasSuffix(verb, "e") && n > 2 &&
+		!strings.HasSuffix(verb, "ee") &&
+		!strings.HasSuffix(verb, "ye") &&
+		!strings.HasSuffix(verb, "oe"):
+		return verb[:n-1] + "ing"
+	case n >= 3 && syllables(verb) == 1 &&
+		!isVowel(verb[n-1]) && strings.IndexByte("wxy", verb[n-1]) < 0 &&
+		isVowel(verb[n-2]) && !isVowel(verb[n-3]):
+		return verb + verb[n-1:] + "ing"
+	}
+	return verb + "ing"
+}
+
+// Number of groups of vowels in word
+func syllables(word string) int {
+	n := 0
+	for i := 0; i < len(word); i++ {
+		if isVowel(word[i]) && (i == 0 || !isVowel(word[i-1])) {
+			n++
+		}
+	}
+	return n
+}
+
+func isVowel(c byte) bool {
+	return strings.IndexByte("aeiou", c) >= 0
+}
@@ -0,0 +1,35 @@
+package gown
+
+import (
+	"testing"
+)
+
+func TestRenderFrame(t *testing.T) {
+	tests := []struct {
+		number int
+		verb   string
+		want   string
+	}{
+		{2, "cry", "Somebody cries"},
+		{2, "play", "Somebody plays"},
+		{2, "catch", "Somebody catches"},
+		{2, "go", "Somebody goes"},
+		{2, "be", "Somebody is"},
+		{8, "have", "Somebody has something"},
+		{2, "saw_wood", "Somebody saws wood"},
+		{3, "die", "It is dying"},
+		{3, "run", "It is running"},
+		{3, "make", "It is making"},
+		{3, "see", "It is seeing"},
+		{3, "snow", "It is snowing"},
+		{3, "permit", "It is permiting"},
+		{4, "break_up", "Something is breaking up PP"},
+		{0, "run", ""},
+		{NUMFRAMES + 1, "run", ""},
+	}
+	for _, test := range tests {
+		if got := RenderFrame(test.number, test.verb); got != test.want {
+			t.Errorf("RenderFrame(%d, %s) = %q; want %q", test.number, test.verb, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,116 @@
+package gown
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+var freqcats []string = []string{
+	"extremely rare",
+	"very rare",
+	"rare",
+	"uncommon",
+	"common",
+	"familiar",
+	"very familiar",
+	"extremely familiar",
+}
+
+var a_an []string = []string{"", "a noun", "a verb", "an adjective", "an adverb"}
+
+// Result of the FREQ search
+type Familiarity struct {
+	Word     string
+	Pos      int    // NOUN, VERB, ADJ or ADV
+	Polysemy int    // number of senses of the word
+	Category string // "rare", "common", ... (see freqcats)
+}
+
+// "dog used as a noun is common (polysemy count = 7)"
+func (f Familiarity) String() string {
+	return fmt.Sprintf("%s used as %s is %s (polysemy count = %d)", strings.Replace(f.Word, "_", " ", -1), a_an[f.Pos], f.Category, f.Polysemy)
+}
+
+// Returns the number of times the sense has been tagged in the semantic
+// concordances, as found in cntlist.rev (0 if the sense is not there)
+// Needs the CNTLIST_FILE optional file
+func (wndb *WordNetDb) TagCount(senseKey string) (int, error) {
+	if wndb.cntList == nil {
+		return 0, ERR_MSG(FILE_NOT_LOADED)
+	}
+	sk, err := ParseSenseKey(strings.ToLower(senseKey))
+	if err != nil {
+		return 0, err
+	}
+	// cntlist.rev: sense_key sense_number tag_cnt
+	line, err := wndb.cntList.lookupLine([]byte(sk.String()))
+	if err == ERR_MSG(UNKNOWN_WORD) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	fields := bytes.Fields(line)
+	if len(fields) != 3 {
+		return 0, errors.New(fmt.Sprintf("Invalid line in cntlist.rev: [%s]", line))
+	}
+	return strconv.Atoi(string(fields[2]))
+}
+
+// Returns the tag count of each sense of word, in sense number order
+// Needs the CNTLIST_FILE optional file
+func (wndb *WordNetDb) SenseTagCounts(word string, pos int) ([]int, error) {
+	lemma := string(strToLower([]byte(word)))
+	offsets, err := wndb.Index.Lookup([]byte(lemma), pos)
+	if err != nil {
+		return nil, err
+	}
+	counts := make([]int, len(offsets))
+	for i, offset := range offsets {
+		s, err := wndb.Synset(pos, offset)
+		if err != nil {
+			return nil, err
+		}
+		sk, err := wndb.SenseKeyOf(lemma, s)
+		if err != nil {
+			return nil, err
+		}
+		counts[i], err = wndb.TagCount(sk.String())
+		if err != nil {
+			return nil, err
+		}
+	}
+	return counts, nil
+}
+
+// FREQ search: familiarity of word from its polysemy count
+func (wndb *WordNetDb) Freq(word string, pos int) (*Familiarity, error) {
+	if pos < 1 || pos > NUMPARTS {
+		return nil, errors.New(fmt.Sprintf("Invalid part of speech: %d", pos))
+	}
+	lemma := string(strToLower([]byte(word)))
+	offsets, err := wndb.Index.Lookup([]byte(lemma), pos)
+	if err != nil {
+		return nil, err
+	}
+	cnt := len(offsets)
+	var familiar int
+	switch {
+	case cnt <= 2:
+		familiar = cnt
+	case cnt <= 4:
+		familiar = 3
+	case cnt <= 8:
+		familiar = 4
+	case cnt <= 16:
+		familiar = 5
+	case cnt <= 32:
+		familiar = 6
+	default:
+		familiar = 7
+	}
+	return &Familiarity{Word: lemma, Pos: pos, Polysemy: cnt, Category: freqcats[familiar]}, nil
+}
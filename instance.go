@@ -0,0 +1,108 @@
+package gown
+
+// Tells if s is an instance (a named entity like "Paris") rather than a class,
+// i.e. if it has instance hypernyms ("@i")
+func IsInstance(s *Synset) bool {
+	for _, ptr := range s.Pointers {
+		if ptr.Symbol == ptrtyp[INSTANCE] {
+			return true
+		}
+	}
+	return false
+}
+
+// Class hypernyms of s ("@"), without the instance hypernyms
+func (wndb *WordNetDb) ClassHypernyms(s *Synset) ([]*Synset, error) {
+	return wndb.pointedSynsets(s, ptrtyp[HYPERPTR])
+}
+
+// Classes s is an instance of ("@i")
+func (wndb *WordNetDb) InstanceHypernyms(s *Synset) ([]*Synset, error) {
+	return wndb.pointedSynsets(s, ptrtyp[INSTANCE])
+}
+
+// Hyponyms of s ("~"), without the instances
+func (wndb *WordNetDb) ClassHyponyms(s *Synset) ([]*Synset, error) {
+	return wndb.pointedSynsets(s, ptrtyp[HYPOPTR])
+}
+
+// Instances of s ("~i") and, if recursive, of all its hyponyms
+// Each instance is returned once
+func (wndb *WordNetDb) Instances(s *Synset, recursive bool) ([]*Synset, error) {
+	if !recursive {
+		return wndb.pointedSynsets(s, ptrtyp[INSTANCES])
+	}
+	tree, err := wndb.TracePointers(s, []string{ptrtyp[HYPOPTR]}, 0)
+	if err != nil {
+		return nil, err
+	}
+	seen := map[synsetId]bool{}
+	instances := make([]*Synset, 0, 4)
+	var walk func(node *SynsetTree) error
+	walk = func(node *SynsetTree) error {
+		found, err := wndb.pointedSynsets(node.Synset, ptrtyp[INSTANCES])
+		if err != nil {
+			return err
+		}
+		for _, inst := range found {
+			if !seen[idOf(inst)] {
+				seen[idOf(inst)] = true
+				instances = append(instances, inst)
+			}
+		}
+		for _, child := range node.Children {
+			if err := walk(child); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if err := walk(tree); err != nil {
+		return nil, err
+	}
+	return instances, nil
+}
+
+// Instances of all the noun senses of word and of their hyponyms
+// (InstancesOf("city") => "Paris", ...)
+func (wndb *WordNetDb) InstancesOf(word string) ([]*Synset, error) {
+	offsets, err := wndb.Index.Lookup(strToLower([]byte(word)), NOUN)
+	if err != nil {
+		return nil, err
+	}
+	seen := map[int64]bool{}
+	instances := make([]*Synset, 0, 4)
+	for _, offset := range offsets {
+		s, err := wndb.Synset(NOUN, offset)
+		if err != nil {
+			return nil, err
+		}
+		found, err := wndb.Instances(s, true)
+		if err != nil {
+			return nil, err
+		}
+		for _, inst := range found {
+			if !seen[inst.Offset] {
+				seen[inst.Offset] = true
+				instances = append(instances, inst)
+			}
+		}
+	}
+	return instances, nil
+}
+
+// Synsets pointed to by the pointers of s with the symbol
+func (wndb *WordNetDb) pointedSynsets(s *Synset, symbol string) ([]*Synset, error) {
+	synsets := make([]*Synset, 0, 2)
+	for _, ptr := range s.Pointers {
+		if ptr.Symbol != symbol {
+			continue
+		}
+		target, err := wndb.Synset(getpos(ptr.Pos), ptr.Offset)
+		if err != nil {
+			return nil, err
+		}
+		synsets = append(synsets, target)
+	}
+	return synsets, nil
+}
@@ -1,13 +1,15 @@
 package gown
 
 import (
+	"errors"
+	"fmt"
 	"os"
 	"io"
-	"fmt"
 	"bytes"
 	"bufio"
 	"strconv"
 	"log"
+	"io/fs"
 )
 
 const (
@@ -16,9 +18,10 @@ const (
 	SYNSET_CNT           // 2
 	PTR_CNT              // 3
 	SYMBOL               // 4
-	SENSE_CNT            // 5
-	TAGSENSE_CNT         // 6
-	SYNSET_OFFSET        // 7
+	// the following fields are after the p_cnt pointer symbols
+	SENSE_CNT     = iota - 1 // 4 + p_cnt
+	TAGSENSE_CNT             // 5 + p_cnt
+	SYNSET_OFFSET            // 6 + p_cnt
 )
 
 // Error msgs -- may be refactored in another src file
@@ -29,15 +32,11 @@ const (
 	LINE_TOO_LONG
 	UNREACHABLE_CODE
 	NOT_A_VALID_FILE_POINTER
+	UNKNOWN_INDEX_TYPE
+	FILE_NOT_LOADED
 )
 
-const (
-	NINDEXRECS = 363000 // Current number of lines in index.* (wc -l index.*)
-	NUMPARTS = 4 // noun, adj, verb, adverb
-	//	NUMFRAMES = 35
-)
-
-var partnames []string = []string{"", "noun", "verb", "adj", "adv"}
+const NINDEXRECS = 363000 // Current number of lines in index.* (wc -l index.*)
 
 type indexInfo struct {
 	lemma        []byte
@@ -59,16 +58,39 @@ func (t ERR_MSG) Error() string {
 type indexMap map[string]indexInfo // TODO: Profile in-memory indexing (better *indexInfo?)
 type indexMaps [NUMPARTS+1]indexMap
 
+// Implementations of Indexer must be safe for concurrent use
 type Indexer interface {
 	Lookup([]byte, int) ([]int64, error)
 }
 
+// Implemented by the indexes of this package to get the whole index entry
+type infoIndexer interface {
+	lookupInfo([]byte, int) (*indexInfo, error)
+}
+
 type indexFiles []io.Reader
-type dataFiles []io.Reader
+type dataFiles []io.ReaderAt
 
+// A WordNetDb is safe for concurrent use by multiple goroutines: the indexes are
+// read-only once loaded and the files are only accessed with positional reads
+// (io.ReaderAt), so no file offset is shared between lookups.
+// Close must not be called while lookups are in progress
 type WordNetDb struct {
 	Index Indexer
 	Data  dataFiles
+
+	fsys   fs.FS
+	logger *log.Logger
+
+	exceptions [NUMPARTS + 1]lineIndex // morphological exception lists (*.exc)
+
+	// Optional files (nil if not loaded)
+	senseIndex  lineIndex // index.sense
+	cntList     lineIndex // cntlist.rev
+	vSentIndex  lineIndex // sentidx.vrb
+	vSents      lineIndex // sents.vrb
+	keyIndex    lineIndex // index.key
+	revKeyIndex lineIndex // index.key.rev
 }
 
 func errMsg(n int) string {
@@ -83,33 +105,92 @@ func errMsg(n int) string {
 		return "UNREACHABLE CODE"
 	case NOT_A_VALID_FILE_POINTER :
 		return "NOT A VALID FILE POINTER"
+	case UNKNOWN_INDEX_TYPE :
+		return "UNKNOWN INDEX TYPE"
+	case FILE_NOT_LOADED :
+		return "OPTIONAL FILE NOT LOADED (see WithFiles)"
 	default :
 		return "UNKNOWN ERROR MSG"
 	}
-	return "" // will not reach here
 }
 
 
 func (i *indexMaps) Lookup(b []byte, pos int) ([]int64, error) {
-	m := i[pos] // returns an indexMap
-	lemma, ok := m[string(b)]
+	lemma, err := i.lookupInfo(b, pos)
+	if err != nil {
+		return nil, err
+	}
+	return lemma.offsets, nil
+}
+
+func (i *indexMaps) lookupInfo(b []byte, pos int) (*indexInfo, error) {
+	if pos < 1 || pos > NUMPARTS {
+		return nil, errors.New(fmt.Sprintf("Invalid part of speech: %d", pos))
+	}
+	lemma, ok := i[pos][string(b)]
 	if !ok {
 		return nil, ERR_MSG(UNKNOWN_WORD)
 	}
-	return lemma.offsets, nil
+	return &lemma, nil
+}
+
+// Index strategies
+const (
+	INDEX_IN_MEMORY  = iota // all the lemmas are loaded in a map
+	INDEX_BIN_SEARCH        // binary search over the index files on disk
+)
+
+// Opens the WordNet database in $WNSEARCHDIR
+func New(indexType int) (*WordNetDb, error) {
+	return Open(os.Getenv("WNSEARCHDIR"), WithIndex(indexType))
 }
 
-func New() (*WordNetDb, error) {
-	searchdir := os.Getenv("WNSEARCHDIR")
+// Opens the WordNet database found in dir
+func Open(dir string, opts ...Option) (*WordNetDb, error) {
+	return OpenFS(os.DirFS(dir), opts...)
+}
+
+// Opens the WordNet database found at the root of fsys (embed.FS, zip.Reader, ...)
+// By default the index is loaded in memory, the progress is logged to stderr
+// and none of the optional files is loaded
+func OpenFS(fsys fs.FS, opts ...Option) (*WordNetDb, error) {
+	o := options{
+		indexType: INDEX_IN_MEMORY,
+		logger:    log.New(os.Stderr, "", 0),
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.logger == nil {
+		o.logger = log.New(io.Discard, "", 0)
+	}
+
 	var err error
-	wndb := WordNetDb{}
+	wndb := WordNetDb{fsys: fsys, logger: o.logger}
+
+	switch o.indexType {
+	case INDEX_IN_MEMORY:
+		wndb.Index, err = loadIndex(fsys, o.logger)
+	case INDEX_BIN_SEARCH:
+		wndb.Index, err = loadBinSearchIndex(fsys)
+	default:
+		err = ERR_MSG(UNKNOWN_INDEX_TYPE)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	wndb.Data, err = dataFh(fsys, o.logger)
+	if err != nil {
+		return nil, err
+	}
 
-	wndb.Index, err = loadIndex(searchdir)
+	wndb.exceptions, err = loadExceptions(fsys, o.indexType, o.logger)
 	if err != nil {
 		return nil, err
 	}
 
-	wndb.Data, err = dataFh(searchdir)
+	err = wndb.openOptionalFiles(o.files, o.indexType)
 	if err != nil {
 		return nil, err
 	}
@@ -119,15 +200,15 @@ func New() (*WordNetDb, error) {
 // Loads an Indexer
 // For now, the indexes are loaded in a map (in memory)
 // TODO: Check for WordNet version. It is stated more or less at the beginning of the file
-func loadIndex(searchdir string) (Indexer, error) {
-	fmt.Fprintf(os.Stderr, "Reading Index (in memory)...\n") // TODO: Time this
+func loadIndex(fsys fs.FS, logger *log.Logger) (Indexer, error) {
+	logger.Printf("Reading Index (in memory)...") // TODO: Time this
 	var index indexMaps
 
 	for i := 1; i <= NUMPARTS; i++ {
 		indexMap := make(indexMap, NINDEXRECS)
-		indexpath := fmt.Sprintf("%s/index.%s", searchdir, partnames[i]) // TODO: Make this portable
-		fmt.Fprintf(os.Stderr, "Processing index file %s\n", indexpath)
-		indexfh, err := os.Open(indexpath)
+		indexpath := "index." + partnames[i]
+		logger.Printf("Processing index file %s", indexpath)
+		indexfh, err := fsys.Open(indexpath)
 		if err != nil {
 			return nil, err
 		}
@@ -135,15 +216,13 @@ func loadIndex(searchdir string) (Indexer, error) {
 		defer func() {
 			e := indexfh.Close()
 			if e != nil {
-				log.Fatal("Problem closing the file")
+				logger.Printf("Problem closing the file %s: %s", indexpath, e)
 			}
 		}()
 
 		bufindexfh := bufio.NewReader(io.Reader(indexfh))
 		nlines := 0
 		for {
-			nlines++
-			fmt.Fprintf(os.Stderr, "\r%d lines                 ", nlines)
 			line, isPrefix, err := bufindexfh.ReadLine()
 			if isPrefix {
 				return nil, ERR_MSG(LINE_TOO_LONG)
@@ -153,8 +232,9 @@ func loadIndex(searchdir string) (Indexer, error) {
 				break
 			}
 			if err != nil {
-				fmt.Fprintf(os.Stderr, "An error occurred while reading line from (%s)\n%s\n", indexpath, line)
+				return nil, err
 			}
+			nlines++
 			if line[0] == ' ' { // header line
 				continue
 			}
@@ -165,28 +245,92 @@ func loadIndex(searchdir string) (Indexer, error) {
 			key := string(newIndexInfo.lemma)
 			indexMap[key] = *newIndexInfo
 		}
+		logger.Printf("%d lines read from %s", nlines, indexpath)
 	}
-	fmt.Fprintf(os.Stderr, "Done\n")
+	logger.Printf("Done")
 
 	return &index, nil
 }
 
-// Loads an array of io.Reader's containing handlers for the datafiles
-func dataFh(searchdir string) (dataFiles, error) {
+// Loads an array of io.ReaderAt's containing handlers for the datafiles
+func dataFh(fsys fs.FS, logger *log.Logger) (dataFiles, error) {
 	var err error
-	datafps := make([]io.Reader, NUMPARTS+1)
+	datafps := make([]io.ReaderAt, NUMPARTS+1)
 	for i := 1; i <= NUMPARTS; i++ {
-		datapath := fmt.Sprintf("%s/data.%s", searchdir, partnames[i]) // TODO: Make this portable
-		fmt.Fprintf(os.Stderr, "Opening data file: %s in slot %d\n", datapath, i)
-		datafps[i], err = os.Open(datapath)
+		datapath := "data." + partnames[i]
+		logger.Printf("Opening data file: %s in slot %d", datapath, i)
+		datafps[i], _, err = openReaderAt(fsys, datapath)
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "WordNet library error: Can't open datafile (%s)\n", datapath)
+			logger.Printf("WordNet library error: Can't open datafile (%s)", datapath)
 			return nil, err
 		}
 	}
 	return datafps, nil
 }
 
+// Opens the optional files requested with WithFiles
+// They are loaded in memory or searched on disk following the index strategy
+func (wndb *WordNetDb) openOptionalFiles(files int, indexType int) error {
+	optional := []struct {
+		flag int
+		name string
+		fh   *lineIndex
+	}{
+		{SENSE_FILE, "index.sense", &wndb.senseIndex},
+		{CNTLIST_FILE, "cntlist.rev", &wndb.cntList},
+		{VSENT_FILE, "sentidx.vrb", &wndb.vSentIndex},
+		{VSENT_FILE, "sents.vrb", &wndb.vSents},
+		{KEY_FILE, "index.key", &wndb.keyIndex},
+		{KEY_FILE, "index.key.rev", &wndb.revKeyIndex},
+	}
+	for _, f := range optional {
+		if files&f.flag == 0 {
+			continue
+		}
+		wndb.logger.Printf("Opening optional file: %s", f.name)
+		var fh lineIndex
+		var err error
+		if indexType == INDEX_IN_MEMORY {
+			fh, err = loadLineMap(wndb.fsys, f.name)
+		} else {
+			fh, err = openIndexFile(wndb.fsys, f.name)
+		}
+		if err != nil {
+			return err
+		}
+		*f.fh = fh
+	}
+	return nil
+}
+
+// Closes all the files opened by the database
+func (wndb *WordNetDb) Close() error {
+	var err error
+	closers := make([]interface{}, 0, 3*NUMPARTS+6)
+	closers = append(closers, wndb.Index)
+	for _, fh := range wndb.Data {
+		closers = append(closers, fh)
+	}
+	for _, exc := range wndb.exceptions {
+		if exc != nil {
+			closers = append(closers, exc)
+		}
+	}
+	for _, f := range []lineIndex{wndb.senseIndex, wndb.cntList, wndb.vSentIndex, wndb.vSents, wndb.keyIndex, wndb.revKeyIndex} {
+		if f != nil {
+			closers = append(closers, f)
+		}
+	}
+	for _, c := range closers {
+		if closer, ok := c.(io.Closer); ok {
+			if e := closer.Close(); e != nil && err == nil {
+				err = e
+			}
+		}
+	}
+	return err
+}
+
 func parseIndexLine(l []byte) (*indexInfo, error) {
 	//	fmt.Fprintf (os.Stderr, "Processing line:\n%s\n", l)
 	newIndexInfo := indexInfo{}
@@ -211,10 +355,10 @@ func parseIndexLine(l []byte) (*indexInfo, error) {
 	if err != nil {
 		return nil, err
 	}
-	offsets_strs := fields[(SYNSET_OFFSET + ptr_cnt - 1):]
+	offsets_strs := fields[(SYNSET_OFFSET + ptr_cnt):]
 	offsets := make([]int64, len(offsets_strs))
 	for i, offset := range offsets_strs {
-		offsets[i], err = strconv.Atoi64(string(offset))
+		offsets[i], err = strconv.ParseInt(string(offset), 10, 64)
 		if err != nil {
 			return nil, err
 		}
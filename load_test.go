@@ -0,0 +1,75 @@
+package gown
+
+import (
+	"sync"
+	"testing"
+)
+
+// testdata/dict is a small dictionary in the WordNet format (dog, city, sleep,
+// snore, quick, ...) with the exception lists and the optional files
+
+func openTestDb(t *testing.T, indexType int) *WordNetDb {
+	wndb, err := Open("testdata/dict", WithIndex(indexType), WithLogger(nil), WithFiles(SENSE_FILE|CNTLIST_FILE|VSENT_FILE))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { wndb.Close() })
+	return wndb
+}
+
+func synsetOf(t *testing.T, wndb *WordNetDb, word string, pos int) *Synset {
+	offsets, err := wndb.Index.Lookup([]byte(word), pos)
+	if err != nil {
+		t.Fatalf("Lookup(%s, %d): %s", word, pos, err)
+	}
+	s, err := wndb.Synset(pos, offsets[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+// One WordNetDb used from many goroutines, run it with go test -race
+func TestConcurrentSearches(t *testing.T) {
+	for _, indexType := range []int{INDEX_IN_MEMORY, INDEX_BIN_SEARCH} {
+		wndb := openTestDb(t, indexType)
+		dog := synsetOf(t, wndb, "dog", NOUN)
+		sleep := synsetOf(t, wndb, "sleep", VERB)
+
+		var wg sync.WaitGroup
+		errs := make(chan error, 64)
+		for i := 0; i < 16; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for j := 0; j < 20; j++ {
+					if offsets, err := wndb.Index.Lookup([]byte("dog"), NOUN); err != nil || offsets[0] != dog.Offset {
+						errs <- err
+						return
+					}
+					if s, err := wndb.Synset(NOUN, dog.Offset); err != nil || s.Words[0].Lemma != "dog" {
+						errs <- err
+						return
+					}
+					if res, err := wndb.Query("dogs#n#1", "hype"); err != nil || len(res.Senses) != 1 || res.Senses[0].Lemma != "canine" {
+						errs <- err
+						return
+					}
+					if over, err := wndb.Overview("sleep"); err != nil || len(over.Entries) != 2 {
+						errs <- err
+						return
+					}
+					if snore, err := wndb.EntailedBy(sleep, true); err != nil || len(snore) != 1 {
+						errs <- err
+						return
+					}
+				}
+			}()
+		}
+		wg.Wait()
+		close(errs)
+		for err := range errs {
+			t.Errorf("index type %d: unexpected result (error %v)", indexType, err)
+		}
+	}
+}
@@ -0,0 +1,257 @@
+package gown
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+	"strings"
+)
+
+// Port of morph.c from the WordNet library
+
+var sufx []string = []string{
+	// Noun suffixes
+	"s", "ses", "xes", "zes", "ches", "shes", "men", "ies",
+	// Verb suffixes
+	"s", "ies", "es", "es", "ed", "ed", "ing", "ing",
+	// Adjective suffixes
+	"er", "est", "er", "est",
+}
+
+var addr []string = []string{
+	// Noun endings
+	"", "s", "x", "z", "ch", "sh", "man", "y",
+	// Verb endings
+	"", "y", "e", "", "e", "", "e", "",
+	// Adjective endings
+	"", "", "e", "e",
+}
+
+// Position and number of the rules of each pos in sufx and addr
+var sufxOffsets []int = []int{0, 0, 8, 16, 0}
+var sufxCnts []int = []int{0, 8, 8, 4, 0}
+
+var prepositions []string = []string{
+	"to", "at", "of", "on", "off", "in", "out", "up", "down",
+	"from", "with", "into", "for", "about", "between",
+}
+
+// Loads the exception lists (noun.exc, verb.exc, adj.exc and adv.exc)
+// A missing exception list is not an error, the detachment rules are still used
+func loadExceptions(fsys fs.FS, indexType int, logger *log.Logger) ([NUMPARTS + 1]lineIndex, error) {
+	var exceptions [NUMPARTS + 1]lineIndex
+	for i := 1; i <= NUMPARTS; i++ {
+		excpath := partnames[i] + ".exc"
+		if _, err := fs.Stat(fsys, excpath); err != nil {
+			logger.Printf("WordNet library warning: Can't open exception file (%s)", excpath)
+			continue
+		}
+		var err error
+		if indexType == INDEX_IN_MEMORY {
+			exceptions[i], err = loadLineMap(fsys, excpath)
+		} else {
+			exceptions[i], err = openIndexFile(fsys, excpath)
+		}
+		if err != nil {
+			return exceptions, err
+		}
+	}
+	return exceptions, nil
+}
+
+// Returns the lemmas of the database word may be an inflected form of
+// (e.g. "dogs" => "dog", "ran" => "run", "better" => "good", "well"), the word
+// itself first if it's in the database
+// Collocations are morphed word by word ("attorneys_general" => "attorney_general")
+// and verbs followed by a preposition on the verb only ("looking_for" => "look_for")
+func (wndb *WordNetDb) BaseForms(word string, pos int) ([]string, error) {
+	if pos < 1 || pos > NUMPARTS {
+		return nil, errors.New(fmt.Sprintf("Invalid part of speech: %d", pos))
+	}
+	str := string(strToLower([]byte(word)))
+	forms := make([]string, 0, 2)
+	add := func(form string) {
+		if form == "" || !wndb.isDefined(form, pos) {
+			return
+		}
+		for _, f := range forms {
+			if f == form {
+				return
+			}
+		}
+		forms = append(forms, form)
+	}
+
+	add(str)
+	for _, exc := range wndb.excLookup(str, pos) {
+		add(exc)
+	}
+	cnt := cntwords([]byte(str), '-')
+	switch {
+	case pos == VERB && cnt > 1 && hasprep(str):
+		add(wndb.morphprep(str))
+	case cnt == 1:
+		add(wndb.morphword(str, pos))
+	default:
+		// Assume the string is a collocation: morph each word
+		var searchstr bytes.Buffer
+		from := 0
+		for i := 0; i <= len(str); i++ {
+			if i < len(str) && str[i] != '_' && str[i] != '-' {
+				continue
+			}
+			w := str[from:i]
+			if base := wndb.morphword(w, pos); base != "" {
+				w = base
+			}
+			searchstr.WriteString(w)
+			if i < len(str) {
+				searchstr.WriteByte(str[i])
+			}
+			from = i + 1
+		}
+		if searchstr.String() != str {
+			add(searchstr.String())
+		}
+	}
+	return forms, nil
+}
+
+func (wndb *WordNetDb) isDefined(word string, pos int) bool {
+	_, err := wndb.Index.Lookup([]byte(word), pos)
+	return err == nil
+}
+
+// Returns the base forms of word in the exception list of pos
+func (wndb *WordNetDb) excLookup(word string, pos int) []string {
+	if wndb.exceptions[pos] == nil || word == "" {
+		return nil
+	}
+	line, err := wndb.exceptions[pos].lookupLine([]byte(word))
+	if err != nil {
+		return nil
+	}
+	fields := bytes.Fields(line)
+	bases := make([]string, 0, len(fields)-1)
+	for _, field := range fields[1:] {
+		bases = append(bases, string(field))
+	}
+	return bases
+}
+
+// Base form of a single word, from the exception list or the detachment rules
+// Returns "" if no base form is found in the database
+func (wndb *WordNetDb) morphword(word string, pos int) string {
+	// Always check the exception list first
+	if exc := wndb.excLookup(word, pos); len(exc) > 0 {
+		return exc[0]
+	}
+	// Only exception list for ADV
+	if pos == ADV {
+		return ""
+	}
+	end := ""
+	tmpbuf := word
+	if pos == NOUN {
+		if strings.HasSuffix(word, "ful") { // "boxesful" => "boxful"
+			tmpbuf = word[:strings.LastIndex(word, "f")]
+			end = "ful"
+		} else if strings.HasSuffix(word, "ss") || len(word) <= 2 { // noun ending with 'ss' or short words
+			return ""
+		}
+	}
+	// If not in exception list, try applying rules from tables
+	for i := 0; i < sufxCnts[pos]; i++ {
+		retval := wordbase(tmpbuf, i+sufxOffsets[pos])
+		if retval != tmpbuf && wndb.isDefined(retval+end, pos) {
+			return retval + end
+		}
+	}
+	return ""
+}
+
+// Applies the rule ender of sufx and addr to word
+func wordbase(word string, ender int) string {
+	if strings.HasSuffix(word, sufx[ender]) {
+		return word[:len(word)-len(sufx[ender])] + addr[ender]
+	}
+	return word
+}
+
+// Tells if one of the words after the first one of a collocation is a preposition
+func hasprep(s string) bool {
+	for _, w := range strings.Split(s, "_")[1:] {
+		for _, prep := range prepositions {
+			if w == prep {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Morphs a verb followed by a preposition ("looking_for" => "look_for")
+// The verb is assumed to be the first word of the phrase. It is morphed and
+// the rest of the phrase tacked on, also with the last word as a noun
+// ("pulls_one's_socks_up")
+func (wndb *WordNetDb) morphprep(s string) string {
+	restIdx := strings.IndexByte(s, '_')
+	lastIdx := strings.LastIndex(s, "_")
+	rest := s[restIdx:]
+	end := ""
+	if restIdx != lastIdx { // more than 2 words
+		if lastwd := wndb.morphword(s[lastIdx+1:], NOUN); lastwd != "" {
+			end = s[restIdx:lastIdx+1] + lastwd
+		}
+	}
+	word := s[:restIdx]
+
+	try := func(verb string) string {
+		if retval := verb + rest; wndb.isDefined(retval, VERB) {
+			return retval
+		}
+		if end != "" {
+			if retval := verb + end; wndb.isDefined(retval, VERB) {
+				return retval
+			}
+		}
+		return ""
+	}
+
+	// First try to find the verb in the exception list
+	if exc := wndb.excLookup(word, VERB); len(exc) > 0 && exc[0] != word {
+		if retval := try(exc[0]); retval != "" {
+			return retval
+		}
+	}
+	for i := 0; i < sufxCnts[VERB]; i++ {
+		if base := wordbase(word, i+sufxOffsets[VERB]); base != word {
+			if retval := try(base); retval != "" {
+				return retval
+			}
+		}
+	}
+	if end != "" {
+		return word + end
+	}
+	return ""
+}
+
+/* Count the number of underscore or space separated words in a string. */
+func cntwords(s []byte, separator byte) (wdcnt int) {
+	wdcnt = 0
+	for i := 0; i < len(s); i++ {
+		if s[i] == separator || s[i] == ' ' || s[i] == '_' {
+			wdcnt++
+			for ; i < len(s); i++ {
+				if s[i] != separator && s[i] != ' ' && s[i] != '_' {
+					break
+				}
+			}
+		}
+	}
+	wdcnt++
+	return
+}
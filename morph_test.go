@@ -0,0 +1,42 @@
+package gown
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestBaseForms(t *testing.T) {
+	tests := []struct {
+		word  string
+		pos   int
+		forms []string
+	}{
+		{"dog", NOUN, []string{"dog"}},
+		{"Dogs", NOUN, []string{"dog"}},
+		{"canines", NOUN, []string{"canine"}}, // noun.exc
+		{"cities", NOUN, []string{"city"}},
+		{"physical entities", NOUN, []string{"physical_entity"}}, // collocation
+		{"slept", VERB, []string{"sleep"}},                       // verb.exc
+		{"sleeping", VERB, []string{"sleep"}},
+		{"snores", VERB, []string{"snore"}},
+		{"destroyed", VERB, []string{"destroy"}},
+		{"broken", ADJ, []string{"broken"}},
+		{"quicker", ADJ, []string{"quick"}},      // adj.exc
+		{"speedilier", ADV, []string{"quickly"}}, // adv.exc
+		{"quicklier", ADV, []string{}},           // only exception list for adverbs
+		{"sawing_wood", VERB, []string{}},        // "sawing" alone isn't a verb
+		{"xyzzy", NOUN, []string{}},
+	}
+	for _, indexType := range []int{INDEX_IN_MEMORY, INDEX_BIN_SEARCH} {
+		wndb := openTestDb(t, indexType)
+		for _, test := range tests {
+			forms, err := wndb.BaseForms(test.word, test.pos)
+			if err != nil || !reflect.DeepEqual(forms, test.forms) {
+				t.Errorf("BaseForms(%q, %d) = %q, %v; want %q", test.word, test.pos, forms, err, test.forms)
+			}
+		}
+		if _, err := wndb.BaseForms("dog", 0); err == nil {
+			t.Error("BaseForms with pos 0 should fail")
+		}
+	}
+}
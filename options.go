@@ -0,0 +1,43 @@
+package gown
+
+import (
+	"log"
+)
+
+// Optional files of the WordNet database (none of them is loaded by default)
+const (
+	SENSE_FILE   = 1 << iota // index.sense
+	CNTLIST_FILE             // cntlist.rev
+	VSENT_FILE               // sents.vrb and sentidx.vrb
+	KEY_FILE                 // index.key and index.key.rev
+)
+
+type options struct {
+	indexType int
+	logger    *log.Logger
+	files     int
+}
+
+// Option configures how a WordNetDb is opened (see Open)
+type Option func(*options)
+
+// Selects the Indexer used to look up lemmas (INDEX_IN_MEMORY or INDEX_BIN_SEARCH)
+func WithIndex(indexType int) Option {
+	return func(o *options) {
+		o.indexType = indexType
+	}
+}
+
+// Sets the logger used to report the progress of the loading (nil to discard the messages)
+func WithLogger(logger *log.Logger) Option {
+	return func(o *options) {
+		o.logger = logger
+	}
+}
+
+// Selects which optional files are loaded, for example WithFiles(SENSE_FILE|CNTLIST_FILE)
+func WithFiles(files int) Option {
+	return func(o *options) {
+		o.files |= files
+	}
+}
@@ -0,0 +1,154 @@
+package gown
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// Result of the OVERVIEW search (wn -over): all the senses of a word
+type Overview struct {
+	Word    string
+	Entries []OverviewEntry // one per part of speech and base form of Word
+}
+
+// The senses of a lemma in one part of speech
+type OverviewEntry struct {
+	Lemma        string
+	Pos          int // NOUN, VERB, ADJ or ADV
+	TaggedSenses int // number of senses tagged in the semantic concordances (the first ones)
+	Senses       []OverviewSense
+}
+
+// A sense in an overview
+type OverviewSense struct {
+	Number   int // sense number (starting at 1)
+	TagCount int // 0 if the CNTLIST_FILE optional file isn't loaded
+	Synset   *Synset
+	Words    []string // synonyms, with adjective markers ("broken(p)")
+	Antonyms []string // direct antonyms of adjectives, printed as "(vs. slow)"
+}
+
+// OVERVIEW search: the senses of word (or of its base forms) in each part of
+// speech, in sense number order
+func (wndb *WordNetDb) Overview(word string) (*Overview, error) {
+	over := &Overview{Word: word, Entries: make([]OverviewEntry, 0, 2)}
+	for pos := 1; pos <= NUMPARTS; pos++ {
+		forms, err := wndb.BaseForms(word, pos)
+		if err != nil {
+			return nil, err
+		}
+		for _, form := range forms {
+			entry, err := wndb.overviewEntry(form, pos)
+			if err != nil {
+				return nil, err
+			}
+			over.Entries = append(over.Entries, *entry)
+		}
+	}
+	if len(over.Entries) == 0 {
+		return nil, ERR_MSG(UNKNOWN_WORD)
+	}
+	return over, nil
+}
+
+func (wndb *WordNetDb) overviewEntry(lemma string, pos int) (*OverviewEntry, error) {
+	offsets, err := wndb.Index.Lookup([]byte(lemma), pos)
+	if err != nil {
+		return nil, err
+	}
+	entry := &OverviewEntry{Lemma: lemma, Pos: pos, TaggedSenses: -1, Senses: make([]OverviewSense, len(offsets))}
+	if idx, ok := wndb.Index.(infoIndexer); ok {
+		info, err := idx.lookupInfo([]byte(lemma), pos)
+		if err != nil {
+			return nil, err
+		}
+		entry.TaggedSenses = info.tagsense_cnt
+	}
+	for i, offset := range offsets {
+		s, err := wndb.Synset(pos, offset)
+		if err != nil {
+			return nil, err
+		}
+		sense := &entry.Senses[i]
+		sense.Number = i + 1
+		sense.Synset = s
+		sense.Words = make([]string, len(s.Words))
+		for w, word := range s.Words {
+			sense.Words[w] = word.Lemma + word.Marker.String()
+		}
+		if pos == ADJ {
+			ants, err := wndb.ResolvePointers(s, ptrtyp[ANTPTR])
+			if err != nil {
+				return nil, err
+			}
+			for _, ant := range ants {
+				sense.Antonyms = append(sense.Antonyms, ant.Target)
+			}
+		}
+		if wndb.cntList != nil {
+			sk, err := wndb.SenseKeyOf(lemma, s)
+			if err != nil {
+				return nil, err
+			}
+			sense.TagCount, err = wndb.TagCount(sk.String())
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+	return entry, nil
+}
+
+// Renders the overview as wn -over does
+func (over *Overview) String() string {
+	var buf bytes.Buffer
+	for _, entry := range over.Entries {
+		buf.WriteString(entry.String())
+	}
+	return buf.String()
+}
+
+func (entry *OverviewEntry) String() string {
+	var buf bytes.Buffer
+	cnt := len(entry.Senses)
+	plural := "s"
+	if cnt == 1 {
+		plural = ""
+	}
+	fmt.Fprintf(&buf, "\nOverview of %s %s\n\n", partnames[entry.Pos], entry.Lemma)
+	fmt.Fprintf(&buf, "The %s %s has %d sense%s", partnames[entry.Pos], entry.Lemma, cnt, plural)
+	switch {
+	case entry.TaggedSenses < 0:
+		buf.WriteString("\n")
+	case entry.TaggedSenses == 0:
+		buf.WriteString(" (no senses from tagged texts)\n")
+	default:
+		fmt.Fprintf(&buf, " (first %d from tagged texts)\n", entry.TaggedSenses)
+	}
+	buf.WriteString("                                      \n")
+	for _, sense := range entry.Senses {
+		buf.WriteString(sense.String())
+	}
+	return buf.String()
+}
+
+// "1. (42) dog, domestic dog, Canis familiaris -- (a member of the genus Canis ...)"
+func (sense *OverviewSense) String() string {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "%d. ", sense.Number)
+	if sense.TagCount > 0 {
+		fmt.Fprintf(&buf, "(%d) ", sense.TagCount)
+	}
+	for i, word := range sense.Words {
+		if i > 0 {
+			buf.WriteString(", ")
+		}
+		buf.WriteString(strings.Replace(word, "_", " ", -1))
+	}
+	for _, ant := range sense.Antonyms {
+		fmt.Fprintf(&buf, " (vs. %s)", strings.Replace(ant, "_", " ", -1))
+	}
+	fmt.Fprintf(&buf, " -- (%s)\n", sense.Synset.Gloss)
+	return buf.String()
+}
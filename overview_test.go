@@ -0,0 +1,56 @@
+package gown
+
+import (
+	"testing"
+)
+
+// The header line of the senses is padded with spaces, as wn -over prints it
+const overviewPad = "                                      \n"
+
+func TestOverviewString(t *testing.T) {
+	tests := []struct {
+		word string
+		want string
+	}{
+		{"sleep",
+			"\n" +
+				"Overview of noun sleep\n" +
+				"\n" +
+				"The noun sleep has 1 sense (first 1 from tagged texts)\n" +
+				overviewPad +
+				"1. sleep, slumber -- (a natural and periodic state of rest during which consciousness of the world is suspended; \"he didn't get enough sleep last night\"; \"calm as a child in dreamless slumber\")\n" +
+				"\n" +
+				"Overview of verb sleep\n" +
+				"\n" +
+				"The verb sleep has 2 senses (first 2 from tagged texts)\n" +
+				overviewPad +
+				"1. (20) sleep, kip -- (be asleep)\n" +
+				"2. (5) sleep -- (be able to accommodate for sleeping)\n"},
+		{"quick",
+			"\n" +
+				"Overview of adj quick\n" +
+				"\n" +
+				"The adj quick has 1 sense (first 1 from tagged texts)\n" +
+				overviewPad +
+				"1. quick (vs. slow) -- (accomplished rapidly and without delay; \"was quick to make friends\")\n"},
+		{"broken",
+			"\n" +
+				"Overview of adj broken\n" +
+				"\n" +
+				"The adj broken has 1 sense (no senses from tagged texts)\n" +
+				overviewPad +
+				"1. broken(p) -- (physically and forcibly separated into pieces or cracked or split; \"a broken mirror\")\n"},
+	}
+	for _, indexType := range []int{INDEX_IN_MEMORY, INDEX_BIN_SEARCH} {
+		wndb := openTestDb(t, indexType)
+		for _, test := range tests {
+			over, err := wndb.Overview(test.word)
+			if err != nil {
+				t.Fatalf("Overview(%s): %v", test.word, err)
+			}
+			if got := over.String(); got != test.want {
+				t.Errorf("index type %d: Overview(%s) =\n%s\nwant\n%s", indexType, test.word, got, test.want)
+			}
+		}
+	}
+}
@@ -0,0 +1,93 @@
+package gown
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Verbs the adjective is a participle of, following its "<" pointers
+// ("broken" => "break")
+func (wndb *WordNetDb) ParticipleOf(adjective string) ([]Derivation, error) {
+	return wndb.lexicalPairs(adjective, ADJ, ptrtyp[PPLPTR])
+}
+
+// Nouns or adjectives the adjective or adverb pertains to, following its "\"
+// pointers ("quickly" => "quick", "urban" => "city")
+// Both adjectives and adverbs are searched if pos is 0
+func (wndb *WordNetDb) PertainsTo(word string, pos int) ([]Derivation, error) {
+	return wndb.lexicalPairs(word, pos, ptrtyp[PERTPTR])
+}
+
+// Adjectives that are participles of the verb ("break" => "broken")
+// The pointers only go from the adjective to the verb, so the whole data.adj
+// file is read
+func (wndb *WordNetDb) Participles(verb string) ([]Derivation, error) {
+	return wndb.lexicalSources(verb, VERB, ptrtyp[PPLPTR], ADJ)
+}
+
+// Adjectives and adverbs that pertain to word ("quick" => "quickly")
+// The pointers only go from the pertainym, so the whole data.adj and data.adv
+// files are read
+// All the parts of speech of word are searched if pos is 0
+func (wndb *WordNetDb) Pertainyms(word string, pos int) ([]Derivation, error) {
+	return wndb.lexicalSources(word, pos, ptrtyp[PERTPTR], ADJ, ADV)
+}
+
+// Inverse of lexicalPairs: finds in the data files of fromPos the lexical
+// pointers with the symbol to a sense of word
+func (wndb *WordNetDb) lexicalSources(word string, pos int, symbol string, fromPos ...int) ([]Derivation, error) {
+	if pos < 0 || pos > NUMPARTS {
+		return nil, errors.New(fmt.Sprintf("Invalid part of speech: %d", pos))
+	}
+	if pos == 0 {
+		pairs := make([]Derivation, 0, 2)
+		for pos = 1; pos <= NUMPARTS; pos++ {
+			p, err := wndb.lexicalSources(word, pos, symbol, fromPos...)
+			if err != nil && err != ERR_MSG(UNKNOWN_WORD) {
+				return nil, err
+			}
+			pairs = append(pairs, p...)
+		}
+		return pairs, nil
+	}
+
+	lemma := string(strToLower([]byte(word)))
+	offsets, err := wndb.Index.Lookup([]byte(lemma), pos)
+	if err != nil {
+		return nil, err
+	}
+	senses := make(map[int64]int, len(offsets)) // offset => sense number
+	for i, offset := range offsets {
+		senses[offset] = i + 1
+	}
+	pairs := make([]Derivation, 0, 1)
+	filter := []byte(" " + symbol + " ")
+	for _, p := range fromPos {
+		err := wndb.scanData(p, filter, func(s *Synset) error {
+			for _, ptr := range s.Pointers {
+				sense, ok := senses[ptr.Offset]
+				if ptr.Symbol != symbol || !ok || getpos(ptr.Pos) != pos || ptr.Source < 1 || ptr.Target < 1 {
+					continue
+				}
+				target, err := wndb.Synset(pos, ptr.Offset)
+				if err != nil {
+					return err
+				}
+				if ptr.Target > len(target.Words) || strings.ToLower(target.Words[ptr.Target-1].Lemma) != lemma {
+					continue
+				}
+				from, err := wndb.wordSense(s, ptr.Source)
+				if err != nil {
+					return err
+				}
+				pairs = append(pairs, Derivation{*from, WordSense{lemma, partchars[pos], sense}})
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return pairs, nil
+}
@@ -0,0 +1,63 @@
+package gown
+
+import (
+	"errors"
+	"fmt"
+)
+
+// A pointer resolved to the synset and words it links
+// Source and Target are the words linked by a lexical pointer and "" for a
+// semantic pointer, which links the whole synsets
+type WordLink struct {
+	Symbol string
+	Source string  // word of the synset the pointer is from
+	Target string  // word of Synset the pointer is to
+	Synset *Synset // synset the pointer is to
+}
+
+// Tells if the pointer is between two words (antonyms, derivations,
+// pertainyms, participles, ...) rather than between two synsets
+func (p Pointer) IsLexical() bool {
+	return p.Source != 0 || p.Target != 0
+}
+
+// Resolves the pointers of s whose symbol is in symbols (all the pointers if
+// symbols is empty)
+func (wndb *WordNetDb) ResolvePointers(s *Synset, symbols ...string) ([]WordLink, error) {
+	return wndb.resolvePointers(s, 0, symbols)
+}
+
+// Resolves the pointers of s that apply to its word number w (starting at 1):
+// the semantic pointers and the lexical pointers from that word
+func (wndb *WordNetDb) WordPointers(s *Synset, w int, symbols ...string) ([]WordLink, error) {
+	if w < 1 || w > len(s.Words) {
+		return nil, errors.New(fmt.Sprintf("Invalid word number %d for synset %08d", w, s.Offset))
+	}
+	return wndb.resolvePointers(s, w, symbols)
+}
+
+func (wndb *WordNetDb) resolvePointers(s *Synset, w int, symbols []string) ([]WordLink, error) {
+	links := make([]WordLink, 0, len(s.Pointers))
+	for _, ptr := range s.Pointers {
+		if len(symbols) > 0 && !hasSymbol(symbols, ptr.Symbol) {
+			continue
+		}
+		if w != 0 && ptr.IsLexical() && ptr.Source != w {
+			continue
+		}
+		target, err := wndb.Synset(getpos(ptr.Pos), ptr.Offset)
+		if err != nil {
+			return nil, err
+		}
+		link := WordLink{Symbol: ptr.Symbol, Synset: target}
+		if ptr.IsLexical() {
+			if ptr.Source < 1 || ptr.Source > len(s.Words) || ptr.Target < 1 || ptr.Target > len(target.Words) {
+				return nil, errors.New(fmt.Sprintf("Invalid word numbers %02x%02x in pointer from synset %08d", ptr.Source, ptr.Target, s.Offset))
+			}
+			link.Source = s.Words[ptr.Source-1].Lemma
+			link.Target = target.Words[ptr.Target-1].Lemma
+		}
+		links = append(links, link)
+	}
+	return links, nil
+}
@@ -0,0 +1,99 @@
+package gown
+
+import (
+	"errors"
+	"fmt"
+)
+
+// A group of related senses of a word
+type SenseGroup struct {
+	Senses  []int // sense numbers (starting at 1), in order
+	Synsets []*Synset
+}
+
+// RELATIVES search: groups the senses of a verb or an adjective into coarse
+// senses
+// Verb senses are grouped when they are linked by verb group pointers ("$"),
+// adjective senses when they are linked by similar to ("&") or see also ("^")
+// pointers or are satellites of the same head synset
+// The groups are sorted by their first sense and senses without relatives are
+// in a group of their own
+func (wndb *WordNetDb) SenseGroups(word string, pos int) ([]SenseGroup, error) {
+	var symbols []string
+	switch pos {
+	case VERB:
+		symbols = []string{ptrtyp[VERBGROUP]}
+	case ADJ:
+		symbols = []string{ptrtyp[SIMPTR], ptrtyp[SEEALSOPTR]}
+	default:
+		return nil, errors.New(fmt.Sprintf("No sense grouping for part of speech %d", pos))
+	}
+	offsets, err := wndb.Index.Lookup(strToLower([]byte(word)), pos)
+	if err != nil {
+		return nil, err
+	}
+	synsets := make([]*Synset, len(offsets))
+	sense := make(map[int64]int, len(offsets)) // offset => index in synsets
+	for i, offset := range offsets {
+		synsets[i], err = wndb.Synset(pos, offset)
+		if err != nil {
+			return nil, err
+		}
+		sense[offset] = i
+	}
+
+	// Union-find over the senses
+	group := make([]int, len(synsets))
+	for i := range group {
+		group[i] = i
+	}
+	var find func(i int) int
+	find = func(i int) int {
+		if group[i] != i {
+			group[i] = find(group[i])
+		}
+		return group[i]
+	}
+	union := func(i, j int) {
+		i, j = find(i), find(j)
+		if i < j {
+			group[j] = i
+		} else {
+			group[i] = j
+		}
+	}
+	heads := map[int64]int{} // head synset offset => first sense in its cluster
+	for i, s := range synsets {
+		for _, ptr := range s.Pointers {
+			if j, ok := sense[ptr.Offset]; ok && getpos(ptr.Pos) == pos && hasSymbol(symbols, ptr.Symbol) {
+				union(i, j)
+			}
+		}
+		if s.Pos == 's' {
+			head, _, err := wndb.HeadOf(s)
+			if err != nil {
+				return nil, err
+			}
+			if j, ok := heads[head.Offset]; ok {
+				union(i, j)
+			} else {
+				heads[head.Offset] = i
+			}
+		}
+	}
+
+	groups := make([]SenseGroup, 0, len(synsets))
+	index := map[int]int{} // root sense => index in groups
+	for i, s := range synsets {
+		root := find(i)
+		g, ok := index[root]
+		if !ok {
+			g = len(groups)
+			index[root] = g
+			groups = append(groups, SenseGroup{})
+		}
+		groups[g].Senses = append(groups[g].Senses, i+1)
+		groups[g].Synsets = append(groups[g].Synsets, s)
+	}
+	return groups, nil
+}
@@ -1,125 +1,162 @@
 package gown
 
 import (
+	"bufio"
 	"errors"
 	"fmt"
 	"bytes"
 	"io"
+	"math"
 	"os"
 	"strconv"
+	"strings"
 	"encoding/hex" // only for the error types
 )
 
 type Data os.File
 
-const BUFFSIZE = 10
+const BUFFSIZE = 3072 // for reading lines in data
 
-type lemma struct {
-	word   []byte
-	lex_id int // 1-digit hexadecimal integer
+// A word of a synset
+type Word struct {
+	Lemma  string    // as written in the data file (spaces are underscores)
+	LexId  int       // 1-digit hexadecimal integer, unique id in the lexicographer file
+	Marker AdjMarker // syntactic marker of adjectives: ALL_POS (none), PADJ, NPADJ or IPADJ
 }
 
-type synsetPtr struct {
-	symbol    []byte
-	offset    int64
-	pos       byte
-	source    int
-	target    int
+// A pointer to another synset
+// Source and Target are 0 for semantic pointers (between synsets) and the
+// word numbers (starting at 1) for lexical pointers (between words)
+type Pointer struct {
+	Symbol string // as in ptrtyp ("@", "~i", "%p", ...)
+	Offset int64
+	Pos    byte // n => NOUN, v => VERB, a => ADJECTIVE, s => ADJECTIVE SATELLITE, r => ADVERB
+	Source int
+	Target int
 }
 
-type dataData struct {
-	synset_offset int64 // Current byte offset in the file represented as an 8-digit dec integer
-	lex_filenum   int   // 2-digit integer
-	ss_type       byte  // n => NOUN, v => VERB, a => ADJECTIVE, s => ADJECTIVE SATELLITE, r => ADVERB
-	w_cnt         int   // 2-digit hexadecimal integer
-	lemmas        []*lemma
-	p_cnt         int
-	ptrs          []*synsetPtr
-	gloss         []byte
+// A generic sentence frame of a verb synset
+type Frame struct {
+	Number int // index in frametext
+	Word   int // word number the frame applies to (0 if it applies to all the words)
 }
 
-func (wndb *WordNetDb) dataLookup(fh *os.File, offset int64) ([]byte, error) {
-	_, err := fh.Seek(offset, os.SEEK_SET)
+// A synset as read from the data files
+type Synset struct {
+	Offset   int64  // byte offset of the synset in the data file
+	Pos      byte   // n => NOUN, v => VERB, a => ADJECTIVE, s => ADJECTIVE SATELLITE, r => ADVERB
+	LexFile  string // name of the lexicographer file (see lexfiles)
+	Words    []Word
+	Pointers []Pointer
+	Frames   []Frame // data.verb only
+	Gloss    string  // as in the data file: definition and examples
+
+	Definition string   // gloss without the examples
+	Examples   []string // quoted example sentences of the gloss, without the quotes
+
+	lexFilenum int
+}
+
+// Reads the line of the data file starting at offset
+// ReadAt doesn't depend on the file offset, so concurrent lookups don't interfere
+func (wndb *WordNetDb) dataLookup(fh io.ReaderAt, offset int64) ([]byte, error) {
+	return readLineAt(fh, offset, BUFFSIZE) // initial size of the buffer is 3kb
+}
+
+// Returns the synset found at offset in the data file of pos (NOUN, VERB, ADJ or ADV)
+func (wndb *WordNetDb) Synset(pos int, offset int64) (*Synset, error) {
+	if pos < 1 || pos > NUMPARTS {
+		return nil, errors.New(fmt.Sprintf("Invalid part of speech: %d", pos))
+	}
+	dataLine, err := wndb.dataLookup(wndb.Data[pos], offset)
 	if err != nil {
 		return nil, err
 	}
+	return parseDataLine(dataLine)
+}
 
-	buffer := make([]byte, BUFFSIZE) // initial size of the buffer is 3kb
-	line := make([]byte, 0, BUFFSIZE)
-	prevLen := 0
+// Reads the data file of pos sequentially and calls fn on each synset whose
+// line contains filter (all the synsets if filter is empty)
+// The filter is checked before parsing the line, so it saves parsing the
+// synsets that can't match
+func (wndb *WordNetDb) scanData(pos int, filter []byte, fn func(s *Synset) error) error {
+	if pos < 1 || pos > NUMPARTS {
+		return errors.New(fmt.Sprintf("Invalid part of speech: %d", pos))
+	}
+	r := bufio.NewReader(io.NewSectionReader(wndb.Data[pos], 0, math.MaxInt64))
 	for {
-		prevLen = len(line)
-		n, err := fh.Read(buffer) // we read the next 3kb (or less)
+		line, err := r.ReadBytes('\n')
 		if err != nil && err != io.EOF {
-			return nil, err
+			return err
 		}
-		line = append(line, buffer[:]...)
-		until := bytes.IndexByte(buffer, '\n')
-		if until > 0 { // We have a full line
-			return line[:prevLen+until], nil
+		line = bytes.TrimRight(line, "\r\n")
+		// skip the license at the beginning of the file
+		if len(line) > 0 && line[0] != ' ' && bytes.Contains(line, filter) {
+			s, perr := parseDataLine(line)
+			if perr != nil {
+				return perr
+			}
+			if ferr := fn(s); ferr != nil {
+				return ferr
+			}
 		}
-		if err == io.EOF || n < BUFFSIZE {
-			return line, nil
+		if err == io.EOF {
+			return nil
 		}
 	}
-	return nil, ERR_MSG(UNREACHABLE_CODE)
 }
 
-func (wndb *WordNetDb) GetRelation(pos int , offset int64, symbol []byte) ([]synsetPtr, error) {
-	fh := wndb.Data[pos]
-	osFh, ok := fh.(*os.File)
-	if !ok {
-		return nil, ERR_MSG(NOT_A_VALID_FILE_POINTER)
-	}
-	dataLine, err := wndb.dataLookup(osFh, offset)
+// Returns the pointers of the synset whose symbol is exactly symbol
+// ("@" doesn't match "@i")
+func (wndb *WordNetDb) GetRelation(pos int , offset int64, symbol []byte) ([]Pointer, error) {
+	s, err := wndb.Synset(pos, offset)
 	if err != nil {
 		return nil, err
 	}
-	ptrs := make([]synsetPtr, 0, 2) // larger cap?
-	for {
-		posInLine := bytes.Index(dataLine, symbol)
-		if posInLine < 0 { // no occurrence
-			return ptrs, nil
+	ptrs := make([]Pointer, 0, 2) // larger cap?
+	for _, ptr := range s.Pointers {
+		if ptr.Symbol == string(symbol) {
+			ptrs = append(ptrs, ptr)
 		}
-		
-		ptr, newPos, err := nextPtr(dataLine, posInLine)
-		if err != nil {
-			return nil, err
-		}
-		dataLine = dataLine[newPos:]
-		ptrs = append(ptrs, *ptr)
 	}
-	return nil, ERR_MSG(UNREACHABLE_CODE)
+	return ptrs, nil
 }
 
-func parseDataLine(dataLine []byte) (*dataData, error) {
-	data := &dataData{}
+func parseDataLine(dataLine []byte) (*Synset, error) {
+	data := &Synset{}
 	var err error
 
 	// gloss
-	lastIndex := bytes.LastIndex(dataLine, []byte{'|', ' '})
-	if lastIndex == -1 {
+	glossIndex := bytes.Index(dataLine, []byte{'|', ' '})
+	if glossIndex == -1 {
 		return nil, errors.New(`No gloss delimiter found "| " in line`)
 	}
-	data.gloss = dataLine[lastIndex+2:]
+	data.Gloss = string(bytes.TrimRight(dataLine[glossIndex+2:], " "))
+	data.Definition, data.Examples = splitGloss(data.Gloss)
+	dataLine = dataLine[:glossIndex]
+	if len(dataLine) < 17 {
+		return nil, errors.New(fmt.Sprintf("Data line too short: [%s]", dataLine))
+	}
 
-	// synset_offset  --- not used
+	// synset_offset
 	synsetOffsetBytes := dataLine[:8]
-	fmt.Printf("synsetOffsetBytes: [%s]\n", synsetOffsetBytes)
-	synset_offset, err := strconv.Atoi64(string(synsetOffsetBytes))
+	synset_offset, err := strconv.ParseInt(string(synsetOffsetBytes), 10, 64)
 	if err != nil {
 		return nil, err
 	}
-	data.synset_offset = synset_offset
+	data.Offset = synset_offset
 
-	// lex_filenum  --- not used
+	// lex_filenum
 	lexFilenumBytes := dataLine[9:11]
-	fmt.Printf("lexFilenumBytes: [%s]\n", lexFilenumBytes)
 	lexFilenum, err := strconv.Atoi(string(lexFilenumBytes))
 	if err != nil {
 		return nil, err
 	}
-	data.lex_filenum = lexFilenum
+	if lexFilenum < 0 || lexFilenum >= len(lexfiles) {
+		return nil, errors.New(fmt.Sprintf("Invalid lex_filenum: %d", lexFilenum))
+	}
+	data.LexFile = lexfiles[lexFilenum]
+	data.lexFilenum = lexFilenum
 
 	// ss_type
 	switch ss_type := dataLine[12]; {
@@ -128,44 +165,43 @@ func parseDataLine(dataLine []byte) (*dataData, error) {
 		ss_type == 'a' ||
 		ss_type == 's' ||
 		ss_type == 'r':
-		data.ss_type = ss_type
+		data.Pos = ss_type
 	default:
-		return nil, errors.New(fmt.Sprintf("Invalid ss_type: %c\n", ss_type))
+		return nil, errors.New(fmt.Sprintf("Invalid ss_type: %c", ss_type))
 	}
 
 	// w_cnt
 	w_cntBytes := dataLine[14:16]
-	fmt.Printf("w_cntBytes: [%s]\n", w_cntBytes)
 	w_cnt, err := x2i(w_cntBytes)
 	if err != nil {
 		return nil, err
 	}
-	data.w_cnt = w_cnt
 
-	// lemmas
-	lemmas := make([]*lemma, w_cnt)
+	// words
+	words := make([]Word, w_cnt)
 	fromPos := 17
 	for i := 0; i < w_cnt; i++ {
-		nextLemma, posInLine, err := nextSense(dataLine, fromPos)
+		nextWord, posInLine, err := nextSense(dataLine, fromPos)
 		if err != nil {
 			return nil, err
 		}
 		fromPos = posInLine
-		lemmas[i] = nextLemma
+		words[i] = *nextWord
 	}
-	data.lemmas = lemmas
+	data.Words = words
 
 	// p_cnt
+	if fromPos+3 > len(dataLine) {
+		return nil, errors.New(fmt.Sprintf("No p_cnt found in line: [%s]", dataLine))
+	}
 	p_cntBytes := dataLine[fromPos:fromPos+3]
-	fmt.Printf("p_cnt: [%s]\n", p_cntBytes)
 	p_cnt, err := strconv.Atoi(string(p_cntBytes))
 	if err != nil {
 		return nil, err
 	}
-	data.p_cnt = p_cnt
 
 	// ptrs
-	ptrs := make([]*synsetPtr, p_cnt)
+	ptrs := make([]Pointer, p_cnt)
 	fromPos = fromPos + 4
 	for i := 0; i < p_cnt; i++ {
 		nextPtr, posInLine, err := nextPtr(dataLine, fromPos)
@@ -173,59 +209,83 @@ func parseDataLine(dataLine []byte) (*dataData, error) {
 			return nil, err
 		}
 		fromPos = posInLine
-		ptrs[i] = nextPtr
+		ptrs[i] = *nextPtr
 	}
-	data.ptrs = ptrs
+	data.Pointers = ptrs
 
-	//frames: In data.verb only, a list of numbers corresponding to the generic verb sentence frames for word s in the synset. frames is of the form:
+	// frames: In data.verb only, f_cnt followed by a list of "+ f_num w_num"
+	if data.Pos == 'v' {
+		if fromPos+2 > len(dataLine) {
+			return nil, errors.New(fmt.Sprintf("No f_cnt found in line: [%s]", dataLine))
+		}
+		f_cntBytes := dataLine[fromPos:fromPos+2]
+		f_cnt, err := strconv.Atoi(string(f_cntBytes))
+		if err != nil {
+			return nil, err
+		}
+		frames := make([]Frame, f_cnt)
+		fromPos = fromPos + 3
+		for i := 0; i < f_cnt; i++ {
+			nextFrame, posInLine, err := nextFrame(dataLine, fromPos)
+			if err != nil {
+				return nil, err
+			}
+			fromPos = posInLine
+			frames[i] = *nextFrame
+		}
+		data.Frames = frames
+	}
 
 	return data, nil
 }
 
-func nextSense(line []byte, pos int) (*lemma, int, error) {
-	lemma := &lemma{}
-	acc := make([]byte, 30)
-	from := pos
-	for i, ch := range line[pos:] {
-		if ch == ' ' {
-			lemma.word = acc
-			from += i + 1
-			break
+func nextSense(line []byte, pos int) (*Word, int, error) {
+	word := &Word{}
+	end := bytes.IndexByte(line[pos:], ' ')
+	if end <= 0 || pos+end+1 >= len(line) {
+		return nil, 0, errors.New(fmt.Sprintf("Invalid word at position %d in line: [%s]", pos, line))
+	}
+	lemma := line[pos : pos+end]
+	if marker := bytes.IndexByte(lemma, '('); marker > 0 {
+		for i := PADJ; i <= IPADJ; i++ {
+			if string(lemma[marker:]) == adjclass[i] {
+				word.Marker = i
+			}
 		}
-		acc[i] = ch
+		lemma = lemma[:marker]
 	}
+	word.Lemma = string(lemma)
+
+	from := pos + end + 1
 	xval := line[from]
 	ival, ok := fromHexChar(xval)
 	if !ok {
 		return nil, 0, errors.New(fmt.Sprintf("Invalid hex byte (%c)", xval))
 	}
-	lemma.lex_id = int(ival)
+	word.LexId = int(ival)
 
-	return lemma, (from + 2), nil
+	return word, (from + 2), nil
 }
 
-func nextPtr(line []byte, pos int) (*synsetPtr, int, error) {
-	ptr := &synsetPtr{}
-	acc := make([]byte, 0, 2)
-	from := pos
-	for i, ch := range line[pos:] {
-		if ch == ' ' {
-			ptr.symbol = acc
-			from += i + 1
-			break
-		}
-		acc = append(acc, ch) // at most 2 digits in symbol
+func nextPtr(line []byte, pos int) (*Pointer, int, error) {
+	ptr := &Pointer{}
+	end := bytes.IndexByte(line[pos:], ' ')
+	if end <= 0 || pos+end+15 > len(line) {
+		return nil, 0, errors.New(fmt.Sprintf("Invalid pointer at position %d in line: [%s]", pos, line))
 	}
+	ptr.Symbol = string(line[pos : pos+end]) // at most 2 chars in symbol
+	from := pos + end + 1
+
 	offsetBytes := line[from:from+8]
-	offset, err := strconv.Atoi64(string(offsetBytes))
+	offset, err := strconv.ParseInt(string(offsetBytes), 10, 64)
 	if err != nil {
 		return nil, 0, err
 	}
-	ptr.offset = offset
+	ptr.Offset = offset
 	from = from + 9
 
 	ptrpos := line[from]
-	ptr.pos = ptrpos
+	ptr.Pos = ptrpos
 
 	from = from+2
 	sourceBytes := line[from:from+2]
@@ -233,7 +293,7 @@ func nextPtr(line []byte, pos int) (*synsetPtr, int, error) {
 	if err != nil {
 		return nil, 0, err
 	}
-	ptr.source = source
+	ptr.Source = source
 
 	from = from+2
 	targetBytes := line[from:from+2]
@@ -241,11 +301,37 @@ func nextPtr(line []byte, pos int) (*synsetPtr, int, error) {
 	if err != nil {
 		return nil, 0, err
 	}
-	ptr.target = target
+	ptr.Target = target
 
 	return ptr, from + 3, nil
 }
 
+// Parses a "+ f_num w_num" frame
+func nextFrame(line []byte, pos int) (*Frame, int, error) {
+	frame := &Frame{}
+	if pos+7 > len(line) || line[pos] != '+' {
+		return nil, 0, errors.New(fmt.Sprintf("Invalid frame at position %d in line: [%s]", pos, line))
+	}
+	from := pos + 2
+
+	numberBytes := line[from:from+2]
+	number, err := strconv.Atoi(string(numberBytes))
+	if err != nil {
+		return nil, 0, err
+	}
+	frame.Number = number
+	from = from + 3
+
+	wordBytes := line[from:from+2]
+	word, err := x2i(wordBytes)
+	if err != nil {
+		return nil, 0, err
+	}
+	frame.Word = word
+
+	return frame, from + 3, nil
+}
+
 // Utility function to convert a 2-digit hexadecimal number to int
 func x2i(src []byte) (int, error) {
 	if len(src) != 2 {
@@ -253,11 +339,11 @@ func x2i(src []byte) (int, error) {
 	}
 	d1, ok := fromHexChar(src[0])
 	if !ok {
-		return 0, hex.InvalidHexCharError(src[0])
+		return 0, hex.InvalidByteError(src[0])
 	}
 	d2, ok := fromHexChar(src[1])
 	if !ok {
-		return 0, hex.InvalidHexCharError(src[1])
+		return 0, hex.InvalidByteError(src[1])
 	}
 	val := int(d1)*16 + int(d2)
 	return val, nil
@@ -275,3 +361,56 @@ func fromHexChar(c byte) (byte, bool) {
 	return 0, false
 }
 
+// Part of speech (NOUN, VERB, ADJ or ADV) of a ss_type or pointer pos
+func getpos(ss_type byte) int {
+	switch ss_type {
+	case 'n':
+		return NOUN
+	case 'v':
+		return VERB
+	case 'a', 's':
+		return ADJ
+	case 'r':
+		return ADV
+	}
+	return 0
+}
+
+// Splits a gloss into its definition and its quoted example sentences
+// ("a young dog; \"the puppy barked\"" => "a young dog", ["the puppy barked"])
+// Semicolons inside quotes don't split the gloss and the attribution after
+// the closing quote of an example ("\"...\"--Winston Churchill") is dropped
+func splitGloss(gloss string) (string, []string) {
+	definition := make([]string, 0, 1)
+	examples := make([]string, 0, 1)
+	add := func(part string) {
+		part = strings.TrimSpace(part)
+		switch {
+		case part == "":
+		case part[0] == '"':
+			if end := strings.IndexByte(part[1:], '"'); end >= 0 {
+				part = part[1 : end+1]
+			} else { // unbalanced quote
+				part = part[1:]
+			}
+			examples = append(examples, part)
+		default:
+			definition = append(definition, part)
+		}
+	}
+	quoted := false
+	from := 0
+	for i := 0; i < len(gloss); i++ {
+		switch gloss[i] {
+		case '"':
+			quoted = !quoted
+		case ';':
+			if !quoted {
+				add(gloss[from:i])
+				from = i + 1
+			}
+		}
+	}
+	add(gloss[from:])
+	return strings.Join(definition, "; "), examples
+}
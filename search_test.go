@@ -0,0 +1,88 @@
+package gown
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSplitGloss(t *testing.T) {
+	tests := []struct {
+		gloss      string
+		definition string
+		examples   []string
+	}{
+		{"a young dog", "a young dog", []string{}},
+		{`a young dog; "the puppy barked"`, "a young dog", []string{"the puppy barked"}},
+		{`be asleep; "she slept"; "he sleeps late"`, "be asleep", []string{"she slept", "he sleeps late"}},
+		{`one; the other; "an example"`, "one; the other", []string{"an example"}},
+		{`make ready; "he prepared; then he left"`, "make ready", []string{"he prepared; then he left"}},
+		{`"it was a great victory"--Winston Churchill`, "", []string{"it was a great victory"}},
+		{`win; "it was a great victory"--Winston Churchill; "we won"`, "win", []string{"it was a great victory", "we won"}},
+		{`unbalanced; "no closing quote`, "unbalanced", []string{"no closing quote"}},
+		{"", "", []string{}},
+	}
+	for _, test := range tests {
+		definition, examples := splitGloss(test.gloss)
+		if definition != test.definition || !reflect.DeepEqual(examples, test.examples) {
+			t.Errorf("splitGloss(%q) = %q, %q; want %q, %q", test.gloss, definition, examples, test.definition, test.examples)
+		}
+	}
+}
+
+func TestParseDataLine(t *testing.T) {
+	line := `02084071 05 n 02 dog 0 Canis_familiaris a 002 @ 02083346 n 0000 + 01234567 v 020f | a member of the genus Canis; "the dog barked"  `
+	s, err := parseDataLine([]byte(line))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Offset != 2084071 || s.Pos != 'n' || s.LexFile != "noun.animal" || s.lexFilenum != 5 {
+		t.Errorf("got offset %d, pos %c, lex file %s (%d)", s.Offset, s.Pos, s.LexFile, s.lexFilenum)
+	}
+	words := []Word{{"dog", 0, ALL_POS}, {"Canis_familiaris", 10, ALL_POS}}
+	if !reflect.DeepEqual(s.Words, words) {
+		t.Errorf("got words %v, want %v", s.Words, words)
+	}
+	ptrs := []Pointer{{"@", 2083346, 'n', 0, 0}, {"+", 1234567, 'v', 2, 15}}
+	if !reflect.DeepEqual(s.Pointers, ptrs) {
+		t.Errorf("got pointers %v, want %v", s.Pointers, ptrs)
+	}
+	if len(s.Frames) != 0 {
+		t.Errorf("got frames %v for a noun", s.Frames)
+	}
+	if s.Gloss != `a member of the genus Canis; "the dog barked"` || s.Definition != "a member of the genus Canis" || !reflect.DeepEqual(s.Examples, []string{"the dog barked"}) {
+		t.Errorf("got gloss %q, definition %q, examples %q", s.Gloss, s.Definition, s.Examples)
+	}
+
+	// verb frames
+	s, err = parseDataLine([]byte("00000815 30 v 02 break 0 split 1 000 03 + 01 00 + 02 00 + 08 02 | become separated into pieces  "))
+	if err != nil {
+		t.Fatal(err)
+	}
+	frames := []Frame{{1, 0}, {2, 0}, {8, 2}}
+	if !reflect.DeepEqual(s.Frames, frames) || len(s.Pointers) != 0 || s.Words[1].LexId != 1 {
+		t.Errorf("got frames %v, pointers %v, words %v", s.Frames, s.Pointers, s.Words)
+	}
+
+	// adjective markers
+	s, err = parseDataLine([]byte("00000478 00 a 03 broken(p) 0 elect(ip) 0 main(a) 0 000 | physically separated  "))
+	if err != nil {
+		t.Fatal(err)
+	}
+	words = []Word{{"broken", 0, PADJ}, {"elect", 0, IPADJ}, {"main", 0, NPADJ}}
+	if !reflect.DeepEqual(s.Words, words) {
+		t.Errorf("got words %v, want %v", s.Words, words)
+	}
+
+	bad := []string{
+		"00000815 30 v 01 break 0 000 00 become separated", // no gloss
+		"0000081 | short", // too short
+		"00000815 30 v 01 break z 000 | bad lex_id",              // lex_id isn't hexadecimal
+		"00000815 30 v 01 break 0 001 < 00000478 a 01 | bad ptr", // truncated source/target
+		"0000o815 30 v 01 break 0 000 | bad offset",
+	}
+	for _, line := range bad {
+		if s, err := parseDataLine([]byte(line)); err == nil {
+			t.Errorf("parseDataLine(%q) = %v, want an error", line, s)
+		}
+	}
+}
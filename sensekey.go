@@ -0,0 +1,187 @@
+package gown
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// A sense key: lemma%ss_type:lex_filenum:lex_id:head_word:head_id
+// head_word and head_id are only present for adjective satellites
+type SenseKey struct {
+	Lemma      string // lowercase, spaces are underscores
+	SsType     int    // NOUN, VERB, ADJ, ADV or ADJSAT
+	LexFilenum int
+	LexId      int
+	HeadWord   string
+	HeadId     int
+}
+
+// An entry of index.sense
+type Sense struct {
+	Key      SenseKey
+	Offset   int64 // offset of the synset in the data file
+	Number   int   // sense number of the lemma (starting at 1)
+	TagCount int   // number of times the sense is tagged in the semantic concordances
+}
+
+// Parses a sense key ("dog%1:05:00::")
+func ParseSenseKey(key string) (*SenseKey, error) {
+	percent := strings.IndexByte(key, '%')
+	if percent <= 0 {
+		return nil, errors.New(fmt.Sprintf("Invalid sense key: %s", key))
+	}
+	fields := strings.Split(key[percent+1:], ":")
+	if len(fields) != 5 {
+		return nil, errors.New(fmt.Sprintf("Invalid sense key: %s", key))
+	}
+	sk := &SenseKey{Lemma: key[:percent], HeadWord: fields[3]}
+	var err error
+	sk.SsType, err = strconv.Atoi(fields[0])
+	if err != nil || sk.SsType < NOUN || sk.SsType > ADJSAT {
+		return nil, errors.New(fmt.Sprintf("Invalid ss_type in sense key: %s", key))
+	}
+	sk.LexFilenum, err = strconv.Atoi(fields[1])
+	if err != nil || sk.LexFilenum < 0 || sk.LexFilenum >= len(lexfiles) {
+		return nil, errors.New(fmt.Sprintf("Invalid lex_filenum in sense key: %s", key))
+	}
+	sk.LexId, err = strconv.Atoi(fields[2])
+	if err != nil {
+		return nil, errors.New(fmt.Sprintf("Invalid lex_id in sense key: %s", key))
+	}
+	// head_word and head_id are given for satellites only
+	if sk.SsType != ADJSAT {
+		if fields[3] != "" || fields[4] != "" {
+			return nil, errors.New(fmt.Sprintf("Head word in a sense key that is not an adjective satellite: %s", key))
+		}
+		return sk, nil
+	}
+	if fields[3] == "" {
+		return nil, errors.New(fmt.Sprintf("No head word in adjective satellite sense key: %s", key))
+	}
+	sk.HeadId, err = strconv.Atoi(fields[4])
+	if err != nil {
+		return nil, errors.New(fmt.Sprintf("Invalid head_id in sense key: %s", key))
+	}
+	return sk, nil
+}
+
+func (sk SenseKey) String() string {
+	if sk.SsType == ADJSAT {
+		return fmt.Sprintf("%s%%%d:%02d:%02d:%s:%02d", sk.Lemma, sk.SsType, sk.LexFilenum, sk.LexId, sk.HeadWord, sk.HeadId)
+	}
+	return fmt.Sprintf("%s%%%d:%02d:%02d::", sk.Lemma, sk.SsType, sk.LexFilenum, sk.LexId)
+}
+
+// Part of speech (NOUN, VERB, ADJ or ADV) of the data file the sense is in
+func (sk SenseKey) Pos() int {
+	if sk.SsType == ADJSAT {
+		return ADJ
+	}
+	return sk.SsType
+}
+
+// Looks up a sense key in index.sense
+// Needs the SENSE_FILE optional file
+func (wndb *WordNetDb) SenseByKey(key string) (*Sense, error) {
+	if wndb.senseIndex == nil {
+		return nil, ERR_MSG(FILE_NOT_LOADED)
+	}
+	sk, err := ParseSenseKey(strings.ToLower(key))
+	if err != nil {
+		return nil, err
+	}
+	// index.sense: sense_key synset_offset sense_number tag_cnt
+	line, err := wndb.senseIndex.lookupLine([]byte(sk.String()))
+	if err != nil {
+		return nil, err
+	}
+	fields := bytes.Fields(line)
+	if len(fields) != 4 {
+		return nil, errors.New(fmt.Sprintf("Invalid line in index.sense: [%s]", line))
+	}
+	sense := &Sense{Key: *sk}
+	sense.Offset, err = strconv.ParseInt(string(fields[1]), 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	sense.Number, err = strconv.Atoi(string(fields[2]))
+	if err != nil {
+		return nil, err
+	}
+	sense.TagCount, err = strconv.Atoi(string(fields[3]))
+	if err != nil {
+		return nil, err
+	}
+	return sense, nil
+}
+
+// Returns the synset of a sense key
+func (wndb *WordNetDb) SynsetByKey(key string) (*Synset, error) {
+	sense, err := wndb.SenseByKey(key)
+	if err != nil {
+		return nil, err
+	}
+	return wndb.Synset(sense.Key.Pos(), sense.Offset)
+}
+
+// Returns the sense key of lemma in the synset s
+// For adjective satellites the head synset is read to get the head word
+func (wndb *WordNetDb) SenseKeyOf(lemma string, s *Synset) (*SenseKey, error) {
+	lemma = string(strToLower([]byte(lemma)))
+	for w, word := range s.Words {
+		if strings.ToLower(word.Lemma) == lemma {
+			return wndb.senseKey(s, w+1)
+		}
+	}
+	return nil, errors.New(fmt.Sprintf("%s is not a word of synset %08d", lemma, s.Offset))
+}
+
+// Sense key of the word number w (starting at 1) of the synset
+func (wndb *WordNetDb) senseKey(s *Synset, w int) (*SenseKey, error) {
+	word := s.Words[w-1]
+	sk := &SenseKey{
+		Lemma:      strings.ToLower(word.Lemma),
+		SsType:     ssType(s.Pos),
+		LexFilenum: s.lexFilenum,
+		LexId:      word.LexId,
+	}
+	if s.Pos == 's' {
+		head, err := wndb.headSynset(s)
+		if err != nil {
+			return nil, err
+		}
+		sk.HeadWord = strings.ToLower(head.Words[0].Lemma)
+		sk.HeadId = head.Words[0].LexId
+	}
+	return sk, nil
+}
+
+// Returns the head synset of an adjective satellite (the first similar to pointer)
+func (wndb *WordNetDb) headSynset(s *Synset) (*Synset, error) {
+	for _, ptr := range s.Pointers {
+		if ptr.Symbol == ptrtyp[SIMPTR] {
+			return wndb.Synset(ADJ, ptr.Offset)
+		}
+	}
+	return nil, errors.New(fmt.Sprintf("No head synset found for adjective satellite %08d", s.Offset))
+}
+
+// ss_type of a synset as a number (as used in sense keys)
+func ssType(pos byte) int {
+	switch pos {
+	case 'n':
+		return NOUN
+	case 'v':
+		return VERB
+	case 'a':
+		return ADJ
+	case 'r':
+		return ADV
+	case 's':
+		return ADJSAT
+	}
+	return 0
+}
@@ -0,0 +1,106 @@
+package gown
+
+import (
+	"testing"
+)
+
+func TestParseSenseKey(t *testing.T) {
+	tests := []struct {
+		key  string
+		want SenseKey
+	}{
+		{"dog%1:05:00::", SenseKey{"dog", NOUN, 5, 0, "", 0}},
+		{"sleep%2:29:01::", SenseKey{"sleep", VERB, 29, 1, "", 0}},
+		{"quick%3:00:00::", SenseKey{"quick", ADJ, 0, 0, "", 0}},
+		{"quickly%4:02:00::", SenseKey{"quickly", ADV, 2, 0, "", 0}},
+		{"fast%5:00:00:quick:00", SenseKey{"fast", ADJSAT, 0, 0, "quick", 0}},
+		{"canis_familiaris%1:05:00::", SenseKey{"canis_familiaris", NOUN, 5, 0, "", 0}},
+	}
+	for _, test := range tests {
+		sk, err := ParseSenseKey(test.key)
+		if err != nil || *sk != test.want {
+			t.Errorf("ParseSenseKey(%s) = %+v, %v; want %+v", test.key, sk, err, test.want)
+			continue
+		}
+		if s := sk.String(); s != test.key {
+			t.Errorf("ParseSenseKey(%s).String() = %s", test.key, s)
+		}
+	}
+
+	bad := []string{
+		"",
+		"dog",
+		"%1:05:00::",
+		"dog%1:05:00:",
+		"dog%6:05:00::",
+		"dog%1:99:00::",
+		"dog%1:05:xx::",
+		"dog%1:05:00:canine:00", // head word of a noun
+		"fast%5:00:00::",        // satellite without head word
+		"fast%5:00:00:quick:",   // satellite without head_id
+	}
+	for _, key := range bad {
+		if sk, err := ParseSenseKey(key); err == nil {
+			t.Errorf("ParseSenseKey(%q) = %+v, want an error", key, sk)
+		}
+	}
+}
+
+func TestSenseByKey(t *testing.T) {
+	for _, indexType := range []int{INDEX_IN_MEMORY, INDEX_BIN_SEARCH} {
+		wndb := openTestDb(t, indexType)
+		tests := []struct {
+			key   string
+			sense Sense
+		}{
+			{"dog%1:05:00::", Sense{SenseKey{"dog", NOUN, 5, 0, "", 0}, 1073, 1, 42}},
+			{"Sleep%2:29:01::", Sense{SenseKey{"sleep", VERB, 29, 1, "", 0}, 567, 2, 5}},
+			{"fast%5:00:00:quick:00", Sense{SenseKey{"fast", ADJSAT, 0, 0, "quick", 0}, 303, 1, 0}},
+		}
+		for _, test := range tests {
+			sense, err := wndb.SenseByKey(test.key)
+			if err != nil || *sense != test.sense {
+				t.Errorf("index type %d: SenseByKey(%s) = %+v, %v; want %+v", indexType, test.key, sense, err, test.sense)
+			}
+		}
+		if sense, err := wndb.SenseByKey("dog%1:05:01::"); err != ERR_MSG(UNKNOWN_WORD) {
+			t.Errorf("index type %d: SenseByKey(dog%%1:05:01::) = %+v, %v; want UNKNOWN_WORD", indexType, sense, err)
+		}
+		if s, err := wndb.SynsetByKey("fast%5:00:00:quick:00"); err != nil || s.Words[0].Lemma != "fast" {
+			t.Errorf("index type %d: SynsetByKey(fast%%5:00:00:quick:00) = %v, %v", indexType, s, err)
+		}
+	}
+
+	wndb, err := Open("testdata/dict", WithLogger(nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer wndb.Close()
+	if _, err := wndb.SenseByKey("dog%1:05:00::"); err != ERR_MSG(FILE_NOT_LOADED) {
+		t.Errorf("SenseByKey without index.sense: got %v, want FILE_NOT_LOADED", err)
+	}
+}
+
+func TestSenseKeyOf(t *testing.T) {
+	wndb := openTestDb(t, INDEX_IN_MEMORY)
+	tests := []struct {
+		lemma string
+		pos   int
+		key   string
+	}{
+		{"dog", NOUN, "dog%1:05:00::"},
+		{"Canis familiaris", NOUN, "canis_familiaris%1:05:00::"},
+		{"fast", ADJ, "fast%5:00:00:quick:00"},
+		{"broken", ADJ, "broken%3:44:00::"},
+	}
+	for _, test := range tests {
+		s := synsetOf(t, wndb, string(strToLower([]byte(test.lemma))), test.pos)
+		sk, err := wndb.SenseKeyOf(test.lemma, s)
+		if err != nil || sk.String() != test.key {
+			t.Errorf("SenseKeyOf(%s) = %v, %v; want %s", test.lemma, sk, err, test.key)
+		}
+	}
+	if sk, err := wndb.SenseKeyOf("cat", synsetOf(t, wndb, "dog", NOUN)); err == nil {
+		t.Errorf("SenseKeyOf(cat, dog) = %v, want an error", sk)
+	}
+}
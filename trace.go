@@ -0,0 +1,208 @@
+package gown
+
+// A synset and the synsets reached from it by following pointers recursively
+type SynsetTree struct {
+	Synset   *Synset
+	Symbol   string // pointer symbol followed to reach the synset ("" for the root)
+	Depth    int    // 0 for the root
+	Children []*SynsetTree
+}
+
+// Follows recursively the pointers of s whose symbol is in symbols (traceptrs in
+// the C library), e.g. TracePointers(s, []string{"@", "@i"}, 0) for the hypernym tree
+// The traversal stops at maxDepth levels (MAXDEPTH if maxDepth <= 0) and synsets
+// already found in the current branch are not followed again, so cycles don't loop
+func (wndb *WordNetDb) TracePointers(s *Synset, symbols []string, maxDepth int) (*SynsetTree, error) {
+	if maxDepth <= 0 {
+		maxDepth = MAXDEPTH
+	}
+	root := &SynsetTree{Synset: s}
+	branch := map[synsetId]bool{idOf(s): true}
+	err := wndb.traceptrs(root, symbols, maxDepth, branch)
+	if err != nil {
+		return nil, err
+	}
+	return root, nil
+}
+
+func (wndb *WordNetDb) traceptrs(node *SynsetTree, symbols []string, maxDepth int, branch map[synsetId]bool) error {
+	if node.Depth >= maxDepth {
+		return nil
+	}
+	for _, ptr := range node.Synset.Pointers {
+		if !hasSymbol(symbols, ptr.Symbol) {
+			continue
+		}
+		id := synsetId{getpos(ptr.Pos), ptr.Offset}
+		if branch[id] { // cycle
+			continue
+		}
+		target, err := wndb.Synset(id.pos, id.offset)
+		if err != nil {
+			return err
+		}
+		child := &SynsetTree{Synset: target, Symbol: ptr.Symbol, Depth: node.Depth + 1}
+		node.Children = append(node.Children, child)
+
+		branch[id] = true
+		err = wndb.traceptrs(child, symbols, maxDepth, branch)
+		delete(branch, id)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Returns the paths from the root to each leaf of the tree
+func (t *SynsetTree) Paths() [][]*Synset {
+	if len(t.Children) == 0 {
+		return [][]*Synset{{t.Synset}}
+	}
+	paths := make([][]*Synset, 0, len(t.Children))
+	for _, child := range t.Children {
+		for _, path := range child.Paths() {
+			paths = append(paths, append([]*Synset{t.Synset}, path...))
+		}
+	}
+	return paths
+}
+
+// Hypernym tree of s, following class and instance hypernyms
+func (wndb *WordNetDb) HypernymTree(s *Synset, maxDepth int) (*SynsetTree, error) {
+	return wndb.TracePointers(s, []string{ptrtyp[HYPERPTR], ptrtyp[INSTANCE]}, maxDepth)
+}
+
+// Hyponym tree of s, following hyponyms and instances
+func (wndb *WordNetDb) HyponymTree(s *Synset, maxDepth int) (*SynsetTree, error) {
+	return wndb.TracePointers(s, []string{ptrtyp[HYPOPTR], ptrtyp[INSTANCES]}, maxDepth)
+}
+
+// Holonym tree of s (member, substance and part holonyms)
+func (wndb *WordNetDb) HolonymTree(s *Synset, maxDepth int) (*SynsetTree, error) {
+	return wndb.TracePointers(s, []string{ptrtyp[ISMEMBERPTR], ptrtyp[ISSTUFFPTR], ptrtyp[ISPARTPTR]}, maxDepth)
+}
+
+// Meronym tree of s (member, substance and part meronyms)
+func (wndb *WordNetDb) MeronymTree(s *Synset, maxDepth int) (*SynsetTree, error) {
+	return wndb.TracePointers(s, []string{ptrtyp[HASMEMBERPTR], ptrtyp[HASSTUFFPTR], ptrtyp[HASPARTPTR]}, maxDepth)
+}
+
+// Hypernym chains of s, from the top of the hierarchy down to s
+// ("entity", "physical_entity", ..., "dog")
+func (wndb *WordNetDb) HypernymChains(s *Synset) ([][]*Synset, error) {
+	tree, err := wndb.HypernymTree(s, 0)
+	if err != nil {
+		return nil, err
+	}
+	chains := tree.Paths()
+	for _, chain := range chains {
+		for i, j := 0, len(chain)-1; i < j; i, j = i+1, j-1 {
+			chain[i], chain[j] = chain[j], chain[i]
+		}
+	}
+	return chains, nil
+}
+
+// Identifies a synset in the database
+type synsetId struct {
+	pos    int
+	offset int64
+}
+
+func idOf(s *Synset) synsetId {
+	return synsetId{getpos(s.Pos), s.Offset}
+}
+
+// Coordinate (sister) terms of a synset under one of its hypernyms
+type Coordinates struct {
+	Hypernym *Synset
+	Terms    []*Synset // hyponyms and instances of Hypernym
+}
+
+// COORDS search: the hyponyms of the hypernyms (class or instance) of s,
+// grouped by hypernym
+// s is not included and a term found under several hypernyms is only listed
+// under the first one
+func (wndb *WordNetDb) CoordinateTerms(s *Synset) ([]Coordinates, error) {
+	seen := map[synsetId]bool{idOf(s): true}
+	coords := make([]Coordinates, 0, 1)
+	for _, ptr := range s.Pointers {
+		if ptr.Symbol != ptrtyp[HYPERPTR] && ptr.Symbol != ptrtyp[INSTANCE] {
+			continue
+		}
+		hypernym, err := wndb.Synset(getpos(ptr.Pos), ptr.Offset)
+		if err != nil {
+			return nil, err
+		}
+		group := Coordinates{Hypernym: hypernym, Terms: make([]*Synset, 0, len(hypernym.Pointers))}
+		for _, hypo := range hypernym.Pointers {
+			if hypo.Symbol != ptrtyp[HYPOPTR] && hypo.Symbol != ptrtyp[INSTANCES] {
+				continue
+			}
+			id := synsetId{getpos(hypo.Pos), hypo.Offset}
+			if seen[id] {
+				continue
+			}
+			seen[id] = true
+			term, err := wndb.Synset(id.pos, id.offset)
+			if err != nil {
+				return nil, err
+			}
+			group.Terms = append(group.Terms, term)
+		}
+		coords = append(coords, group)
+	}
+	return coords, nil
+}
+
+// A meronym or holonym found by an inherited search
+type InheritedPointer struct {
+	Symbol string  // "%m", "%s", "%p" or "#m", "#s", "#p"
+	Synset *Synset // the meronym or holonym
+	From   *Synset // synset it's inherited from (the searched synset or one of its hypernyms)
+	Depth  int     // number of hypernym links between the searched synset and From
+}
+
+// HMERONYM search: member, substance and part meronyms of s and of all its hypernyms
+func (wndb *WordNetDb) InheritedMeronyms(s *Synset) ([]InheritedPointer, error) {
+	return wndb.traceinherit(s, []string{ptrtyp[HASMEMBERPTR], ptrtyp[HASSTUFFPTR], ptrtyp[HASPARTPTR]})
+}
+
+// HHOLONYM search: member, substance and part holonyms of s and of all its hypernyms
+func (wndb *WordNetDb) InheritedHolonyms(s *Synset) ([]InheritedPointer, error) {
+	return wndb.traceinherit(s, []string{ptrtyp[ISMEMBERPTR], ptrtyp[ISSTUFFPTR], ptrtyp[ISPARTPTR]})
+}
+
+// Collects the pointers with the given symbols of s and its hypernyms, nearest first
+func (wndb *WordNetDb) traceinherit(s *Synset, symbols []string) ([]InheritedPointer, error) {
+	tree, err := wndb.HypernymTree(s, 0)
+	if err != nil {
+		return nil, err
+	}
+	found := make([]InheritedPointer, 0, 4)
+	visited := map[synsetId]bool{}
+	level := []*SynsetTree{tree}
+	for len(level) > 0 {
+		next := make([]*SynsetTree, 0, len(level))
+		for _, node := range level {
+			next = append(next, node.Children...)
+			if visited[idOf(node.Synset)] { // reached through several hypernyms
+				continue
+			}
+			visited[idOf(node.Synset)] = true
+			for _, ptr := range node.Synset.Pointers {
+				if !hasSymbol(symbols, ptr.Symbol) {
+					continue
+				}
+				target, err := wndb.Synset(getpos(ptr.Pos), ptr.Offset)
+				if err != nil {
+					return nil, err
+				}
+				found = append(found, InheritedPointer{ptr.Symbol, target, node.Synset, node.Depth})
+			}
+		}
+		level = next
+	}
+	return found, nil
+}
@@ -0,0 +1,84 @@
+package gown
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Returns the example sentences of a verb sense (e.g. "eat%2:34:00::") found in
+// sents.vrb, with the verb filled in ("The children eat in the kitchen")
+// Verbs without specific sentences return an empty list
+// Needs the VSENT_FILE optional files
+func (wndb *WordNetDb) VerbSentences(senseKey string) ([]string, error) {
+	if wndb.vSentIndex == nil || wndb.vSents == nil {
+		return nil, ERR_MSG(FILE_NOT_LOADED)
+	}
+	percent := strings.IndexByte(senseKey, '%')
+	if percent <= 0 {
+		return nil, errors.New(fmt.Sprintf("Invalid sense key: %s", senseKey))
+	}
+	// sentidx.vrb: sense_key sentence_number[,sentence_number...]
+	line, err := wndb.vSentIndex.lookupLine([]byte(senseKey))
+	if err == ERR_MSG(UNKNOWN_WORD) {
+		return []string{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	fields := bytes.Fields(line)
+	if len(fields) < 2 {
+		return []string{}, nil
+	}
+
+	verb := strings.Replace(senseKey[:percent], "_", " ", -1)
+	numbers := bytes.Split(fields[1], []byte{','})
+	sentences := make([]string, 0, len(numbers))
+	for _, number := range numbers {
+		// sents.vrb: sentence_number sentence (with %s in place of the verb)
+		sentLine, err := wndb.vSents.lookupLine(number)
+		if err == ERR_MSG(UNKNOWN_WORD) {
+			wndb.logger.Printf("WordNet warning: sentence %s of %s not found in sents.vrb", number, senseKey)
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		sentence := bytes.TrimSpace(sentLine[len(number):])
+		sentences = append(sentences, strings.Replace(string(sentence), "%s", verb, -1))
+	}
+	return sentences, nil
+}
+
+// FRAMES search for the word number w (starting at 1) of a verb synset
+// Returns the specific example sentences of the word or, if there are none (or
+// the VSENT_FILE optional files are not loaded), the text of its generic frames
+// ("Somebody ----s something")
+func (wndb *WordNetDb) VerbExamples(s *Synset, w int) ([]string, error) {
+	if s.Pos != 'v' {
+		return nil, errors.New(fmt.Sprintf("Synset %08d is not a verb", s.Offset))
+	}
+	if w < 1 || w > len(s.Words) {
+		return nil, errors.New(fmt.Sprintf("Invalid word number %d for synset %08d", w, s.Offset))
+	}
+	if wndb.vSentIndex != nil && wndb.vSents != nil {
+		sk, err := wndb.senseKey(s, w)
+		if err != nil {
+			return nil, err
+		}
+		sentences, err := wndb.VerbSentences(sk.String())
+		if err != nil {
+			return nil, err
+		}
+		if len(sentences) > 0 {
+			return sentences, nil
+		}
+	}
+	frames := s.FramesFor(w)
+	texts := make([]string, len(frames))
+	for i, frame := range frames {
+		texts[i] = frame.Text()
+	}
+	return texts, nil
+}
@@ -1,30 +1,9 @@
 package gown
 
-import (
-	"io"
-)
-
 const (
 	wnrelease = "3.0"
 )
 
-const (
-	LEMMA = iota   // 0
-	POS            // 1
-	SYNSET_CNT     // 2
-	PTR_CNT        // 3
-	SENSE_CNT      // 4
-	TAGSENSE_CNT   // 5
-	SYNSET_OFFSET  // 6
-)
-
-
-const (
-	WORD = iota
-	POS
-	SENSE
-)
-
 // buffer sizes *** should these constants be in another source file? ***
 const (
 	SEARCHBUF = (200*1024) // *** long in wn.h ***
@@ -125,18 +104,12 @@ const (
 )
 
 const (
-	ALL_POS = iota
+	ALL_POS AdjMarker = iota
 	PADJ // (p)
 	NPADJ // (a)
 	IPADJ // (ip)
 )
 
-var (
-//	datafps [NUMPARTS + 1]io.Reader             // *** with or without pointer?? ***
-//	indexfps [NUMPARTS + 1]io.Reader            // *** with or without pointer?? ***
-	sensefp, cntlistfp, keyindexfp, revkeyindexfp, vidxfilefp, vsentfilefp io.Reader
-)
-
 
 var lexfiles []string = []string{
 	"adj.all",		/* 0 */
@@ -1,11 +1,10 @@
 package gown
 
 import (
+	"errors"
 	"fmt"
-	"os"
+	"strconv"
 	"strings"
-	"bytes"
-	"encoding/hex"
 )
 
 const (
@@ -50,36 +49,109 @@ var (
 	// ???
 //}
 
-type lemma struct {
-	word []byte,
-	lex_id int, // 1-digit hexadecimal integer
-}
-
-type dataData struct {
-	synset_offset int64, // Current byte offset in the file represented as an 8-digit dec integer
-	lex_filenum int, // 2-digit integer
-	ss_type byte, // n => NOUN, v => VERB, a => ADJECTIVE, s => ADJECTIVE SATELLITE, r => ADVERB
-	w_cnt int, // 2-digit hexadecimal integer
-	lemmas []*lemma
-	gloss []byte
-
-}
-
+// A "word#pos#sense" query, as in WordNet::QueryData
 type Query struct {
-	option []byte, // user's search request
-	search int, // search to pass findtheinfo()
-	pos int, // part-of-speech to pass findtheinfo()
-	sense int, // Perform search on sense number # only
+	option []byte // user's search request
+	search int // search to pass findtheinfo()
+	pos int // part-of-speech to pass findtheinfo() (0 for all)
+	sense int // Perform search on sense number # only
 	rel []byte // Relation
 	// helpmsgidx int, // index into help message table
 	// label []char, // text for search header message
 }
 
+// Relation names accepted by QuerySense (besides "glos" and "syns")
 var relNameSyn = map[string][]string {
-	"ants" : []string{"!"},
-	"hype" : []string{"@"},
-	"inst" : []string{"@i"},
-	"hypes" : []string{"@", "@i"},
+	"ants" : []string{ptrtyp[ANTPTR]},
+	"hype" : []string{ptrtyp[HYPERPTR]},
+	"inst" : []string{ptrtyp[INSTANCE]},
+	"hypes" : []string{ptrtyp[HYPERPTR], ptrtyp[INSTANCE]},
+	"hypo" : []string{ptrtyp[HYPOPTR]},
+	"hasi" : []string{ptrtyp[INSTANCES]},
+	"hypos" : []string{ptrtyp[HYPOPTR], ptrtyp[INSTANCES]},
+	"mmem" : []string{ptrtyp[HASMEMBERPTR]},
+	"msub" : []string{ptrtyp[HASSTUFFPTR]},
+	"mprt" : []string{ptrtyp[HASPARTPTR]},
+	"mero" : []string{ptrtyp[HASMEMBERPTR], ptrtyp[HASSTUFFPTR], ptrtyp[HASPARTPTR]},
+	"hmem" : []string{ptrtyp[ISMEMBERPTR]},
+	"hsub" : []string{ptrtyp[ISSTUFFPTR]},
+	"hprt" : []string{ptrtyp[ISPARTPTR]},
+	"holo" : []string{ptrtyp[ISMEMBERPTR], ptrtyp[ISSTUFFPTR], ptrtyp[ISPARTPTR]},
+	"attr" : []string{ptrtyp[ATRIBUTE]},
+	"enta" : []string{ptrtyp[ENTAILPTR]},
+	"caus" : []string{ptrtyp[CAUSETO]},
+	"also" : []string{ptrtyp[SEEALSOPTR]},
+	"vgrp" : []string{ptrtyp[VERBGROUP]},
+	"sim" : []string{ptrtyp[SIMPTR]},
+	"part" : []string{ptrtyp[PPLPTR]},
+	"pert" : []string{ptrtyp[PERTPTR]},
+	"deri" : []string{ptrtyp[DERIVATION]},
+	"domn" : []string{ptrtyp[CLASSIF_CATEGORY], ptrtyp[CLASSIF_USAGE], ptrtyp[CLASSIF_REGIONAL]},
+	"dmnc" : []string{ptrtyp[CLASSIF_CATEGORY]},
+	"dmnu" : []string{ptrtyp[CLASSIF_USAGE]},
+	"dmnr" : []string{ptrtyp[CLASSIF_REGIONAL]},
+	"domt" : []string{ptrtyp[CLASS_CATEGORY], ptrtyp[CLASS_USAGE], ptrtyp[CLASS_REGIONAL]},
+	"dmtc" : []string{ptrtyp[CLASS_CATEGORY]},
+	"dmtu" : []string{ptrtyp[CLASS_USAGE]},
+	"dmtr" : []string{ptrtyp[CLASS_REGIONAL]},
+}
+
+// A word, word#pos or word#pos#sense
+type WordSense struct {
+	Lemma string
+	Pos   byte // 'n', 'v', 'a' or 'r' (0 if not given)
+	Sense int  // sense number (0 if not given)
+}
+
+func (ws WordSense) String() string {
+	switch {
+	case ws.Pos == 0:
+		return ws.Lemma
+	case ws.Sense == 0:
+		return fmt.Sprintf("%s#%c", ws.Lemma, ws.Pos)
+	}
+	return fmt.Sprintf("%s#%c#%d", ws.Lemma, ws.Pos, ws.Sense)
+}
+
+// Result of a query
+// word => word#pos, word#pos => word#pos#sense, word#pos#sense + relation => word#pos#sense
+// (or the gloss for "glos")
+type QueryResult struct {
+	Senses []WordSense
+	Gloss  string
+}
+
+// Parses a "word", "word#pos" or "word#pos#sense" query with an optional relation name
+func ParseQuery(q string, rel string) (*Query, error) {
+	query := &Query{rel: []byte(rel)}
+	parts := strings.Split(q, "#")
+	if len(parts) > 3 || parts[0] == "" {
+		return nil, errors.New(fmt.Sprintf("Bad query: %s", q))
+	}
+	query.option = []byte(parts[0])
+	if len(parts) > 1 {
+		if len(parts[1]) != 1 || strings.IndexByte(partchars[1:], parts[1][0]) < 0 {
+			return nil, errors.New(fmt.Sprintf("Bad part of speech in query: %s", q))
+		}
+		query.pos = getpos(parts[1][0])
+	}
+	if len(parts) > 2 {
+		sense, err := strconv.Atoi(parts[2])
+		if err != nil || sense < 1 {
+			return nil, errors.New(fmt.Sprintf("Bad sense number in query: %s", q))
+		}
+		query.sense = sense
+	}
+	return query, nil
+}
+
+// Runs a query like WordNet::QueryData querySense, e.g. Query("dog#n#1", "hype")
+func (wndb *WordNetDb) Query(q string, rel string) (*QueryResult, error) {
+	query, err := ParseQuery(q, rel)
+	if err != nil {
+		return nil, err
+	}
+	return wndb.QuerySense(query)
 }
 
 // type Index struct {
@@ -94,40 +166,6 @@ var relNameSyn = map[string][]string {
 // 	ptruse int // pointers used. ***IN wn.h THIS IS A POINTER TO A INT***
 // }
 
-type Synset struct {
-	hereiam int64 // current file position
-	ssypte int // type of ADJ synset
-	fnum int // file number that synset comes from
-	pos []byte // part of speech
-	wcount int // number of words in synset
-	words [][]byte // words in synset
-	lexid int //unique id in lexicographer file *** In wn.h this is a pointer to a int ***
-	wnsns int // sense number in wordnet *** In wn.h this is a pointer to a int ***
-	whichword int // which word in synsest we're looking for
-	ptrcount int // number of pointers
-	ptrtyp int // pointer types *** In wn.h this is a pointer to a int ***
-	ptroff int64 // pointer offsets *** In wn.h this is a pointer to a long ***
-	ppos int // pointer part of speech *** In wn.h this is a pointer to a int ***
-	pto int // pointer 'to' fields *** In wn.h this is a pointer to a int ***
-	pfrm int // pointer 'from' fields *** In wn.h this is a pointer to a int ***
-	fcount int // number of verb frames
-	frmid int // frame numbers *** In wn.h this is a pointer to a int ***
-	frmto int // frame 'to' fields *** In wn.h this is a pointer to a int ***
-	defn []byte // synset gloss (definition)
-	key uint32 // unique synset key
-
-/* these fields are used if a data structure is returned
- instead of a text buffer */
-
-	nextss *Synset // ptr to next synset containing searchword
-	nextform *Synset // ptr to list of synsets for alternate spelling of wordform
-
-	searchtype int // type of search performed
-	ptrlist []*Synset // ptr to synset list result of search
-	headword []byte // if pos is "s", this is cluster head word
-	headsense int16 // sense number of headword
-}
-
 type SnsIndex struct {
 	sensekey []byte // sense key
 	word []byte // word string
@@ -146,113 +184,149 @@ type SearchResults struct {
 	searchds *Synset // data structure containing search results
 }
 
-// Find word in index file and return parsed entry in data structure
-// Input word must be exact match of string in database (case insensitive)
-func (wndb *WordNetDb) indexOffsetLookup (query Query) []int64 {
-	lword := strToLower(query.option)
-	offsets := wndb.Index.Lookup(lword, query.pos)
+// Find word in index file and return its base form and the offsets of its synsets
+// Inflected forms are looked up by their base form (see BaseForms)
+func (wndb *WordNetDb) indexOffsetLookup (query *Query) (string, []int64, error) {
+	forms, err := wndb.BaseForms(string(query.option), query.pos)
+	if err != nil {
+		return "", nil, err
+	}
+	if len(forms) == 0 {
+		return "", nil, ERR_MSG(UNKNOWN_WORD)
+	}
+	offsets, err := wndb.Index.Lookup([]byte(forms[0]), query.pos)
+	if err != nil {
+		return "", nil, err
+	}
 
 	if query.sense != 0 {
-		offsets = offsets[:query.sense]
+		if query.sense > len(offsets) {
+			return "", nil, ERR_MSG(UNKNOWN_WORD)
+		}
+		offsets = offsets[query.sense-1:query.sense]
 	}
 
-	return offsets
+	return forms[0], offsets, nil
 }
 
-
-func (wndb *WordNetDb) QuerySense (query *Query) [][]byte {
-	fmt.Fprintf(os.Stderr, "(QuerySense) STRING=%s#%d#%d#%d\n", query.option, query.search, query.pos, query.sense) // Only for dubugging
-
-	lword := strToLower(query.option)
-
-	rtn := make([][]byte, 0, 10) // 10?
-
-	if query.sense != 0 {
-		if query.rel == nil {
-			fmt.Fprintf(os.Stderr, "(QuerySense) Relation required\n")
-			os.Exit(1) // return err?
+func (wndb *WordNetDb) QuerySense (query *Query) (*QueryResult, error) {
+	rtn := &QueryResult{Senses: make([]WordSense, 0, 10)} // 10?
+
+	// word => word#pos
+	if query.pos == 0 {
+		for pos := 1; pos <= NUMPARTS; pos++ {
+			forms, err := wndb.BaseForms(string(query.option), pos)
+			if err != nil {
+				return nil, err
+			}
+			for _, form := range forms {
+				rtn.Senses = append(rtn.Senses, WordSense{form, partchars[pos], 0})
+			}
 		}
-		if rel, ok := relSymName[string(query.rel)]; ok {
-			query.rel = rel
-		} else if _, ok := relNameSym[string(query.rel)]; ok || string(query.rel) == "glos" || string(query.rel) == "syns" {
-		} else {
-			fmt.Fprintf(os.Stderr, "(QuerySense) Bad relation: %s\n", query.rel)
-			os.Exit(1) // return err?
-		} 
-
-		offsets = wndb.indexOffsetLookup(lword, query.pos, query.sense)
-		offset = offset[0]
-		dataLine := dataLookup(query.pos, offset)
+		return rtn, nil
+	}
 
-		if rel eq "glos" {
-			lastIndex := bytes.LastIndex(dataLine, "| ")
-			// if lastIndex == -1??
-			rtn = append(rtn, dataLine[lastIndex+1:])
-		} else if rel eq "syns" {
-			allSenses = getAllSenses(query.pos, offset)
+	// word#pos => word#pos#sense
+	if query.sense == 0 {
+		forms, err := wndb.BaseForms(string(query.option), query.pos)
+		if err != nil {
+			return nil, err
 		}
-		
+		for _, form := range forms {
+			offsets, err := wndb.Index.Lookup([]byte(form), query.pos)
+			if err != nil {
+				return nil, err
+			}
+			for i := range offsets {
+				rtn.Senses = append(rtn.Senses, WordSense{form, partchars[query.pos], i + 1})
+			}
+		}
+		return rtn, nil
 	}
-}
-
-func parseDataLine(dataLine []byte) *dataData, os.Error {
-	data := &dataData{}
-	// gloss
-	lastIndex := bytes.LastIndex(dataLine, "| ")
-	data.gloss = dataLine[lastIndex:]
-	return data
-}
-
-func (wndb *WordNetDb) getAllSenses(dataLine []byte) [][]byte {
-	fmt.Fprintf(os.Stderr, "(getAllSenses) line=%s\n", dataLine) // debugging
 
-	rtn := make([][]byte, 0, 10) // 10?
-	dataParts := bytes.SplitN(dataLine, []byte{' '}, 5)
-	w_cnt, err := x2i(dataParts[3])
-
-	words := make([][]byte, w_cnt)
-	for i:=0; i<w_cnt; i++ {
-		words[i]
+	// word#pos#sense + relation
+	rel := string(query.rel)
+	if rel == "" {
+		return nil, errors.New("Relation required")
+	}
+	symbols, ok := relNameSyn[rel]
+	if !ok && rel != "glos" && rel != "syns" {
+		return nil, errors.New(fmt.Sprintf("Bad relation: %s", rel))
 	}
-}
 
-func x2i(src []byte) (int, os.Error) {
-	if len(src) > 2 {
-		return 0, hex.InvalidHexCharError()
+	lemma, offsets, err := wndb.indexOffsetLookup(query)
+	if err != nil {
+		return nil, err
 	}
-	d1, ok := fromHexChar(src[0])
-	if !ok {
-		return 0, hex.InvalidHexCharError(src[0])
+	synset, err := wndb.Synset(query.pos, offsets[0])
+	if err != nil {
+		return nil, err
 	}
-	d2, ok := fromHexChar(src[1])
-	if !ok {
-		return 0, hex.InvalidHexCharError(src[1])
+
+	switch rel {
+	case "glos":
+		rtn.Gloss = synset.Gloss
+	case "syns":
+		for w := range synset.Words {
+			ws, err := wndb.wordSense(synset, w+1)
+			if err != nil {
+				return nil, err
+			}
+			rtn.Senses = append(rtn.Senses, *ws)
+		}
+	default:
+		wordnum := 0
+		for w, word := range synset.Words {
+			if strings.ToLower(word.Lemma) == lemma {
+				wordnum = w + 1
+			}
+		}
+		for _, ptr := range synset.Pointers {
+			if !hasSymbol(symbols, ptr.Symbol) || (ptr.Source != 0 && ptr.Source != wordnum) {
+				continue
+			}
+			target, err := wndb.Synset(getpos(ptr.Pos), ptr.Offset)
+			if err != nil {
+				return nil, err
+			}
+			w := ptr.Target
+			if w == 0 {
+				w = 1
+			}
+			ws, err := wndb.wordSense(target, w)
+			if err != nil {
+				return nil, err
+			}
+			rtn.Senses = append(rtn.Senses, *ws)
+		}
 	}
-	val := int(d1)*16 + int(d2)
-	return val, nil
+	return rtn, nil
 }
 
-fromHexChar(c byte) (byte, bool) {
-	switch {
-	case '0' <= c && c <= '9':
-		return c - '0', true
-	case 'a' <= c && c <= 'f':
-		return c - 'a' + 10, true
-	case 'A' <= c && c <= 'F':
-		return c - 'A' + 10, true
+// Returns the word#pos#sense of the word number w (starting at 1) of the synset
+func (wndb *WordNetDb) wordSense(s *Synset, w int) (*WordSense, error) {
+	if w < 1 || w > len(s.Words) {
+		return nil, errors.New(fmt.Sprintf("Invalid word number %d for synset %08d", w, s.Offset))
+	}
+	pos := getpos(s.Pos)
+	lemma := strings.ToLower(s.Words[w-1].Lemma)
+	offsets, err := wndb.Index.Lookup([]byte(lemma), pos)
+	if err != nil {
+		return nil, err
 	}
-	return 0, false
+	for i, offset := range offsets {
+		if offset == s.Offset {
+			return &WordSense{lemma, partchars[pos], i + 1}, nil
+		}
+	}
+	return nil, errors.New(fmt.Sprintf("Synset %08d not found in the index entry of %s", s.Offset, lemma))
 }
 
-/* Convert to lowercase and remove trailing adjective marker if found */
-func strToLower(str []byte) []byte {
-	ret := make ([]byte, len(str))
-	for i, ch := range str {
-		if ch == '(' {
-			return bytes.ToLower(ret)
+func hasSymbol(symbols []string, symbol string) bool {
+	for _, s := range symbols {
+		if s == symbol {
+			return true
 		}
-		ret[i] = ch
 	}
-	return bytes.ToLower(ret)
+	return false
 }
-